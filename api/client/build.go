@@ -6,10 +6,12 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"strings"
 
 	"golang.org/x/net/context"
 
@@ -23,6 +25,7 @@ import (
 	"github.com/docker/docker/pkg/jsonmessage"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/streamformatter"
 	"github.com/docker/docker/pkg/urlutil"
 	"github.com/docker/docker/reference"
@@ -58,6 +61,9 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 	flCPUSetCpus := cmd.String([]string{"-cpuset-cpus"}, "", "CPUs in which to allow execution (0-3, 0,1)")
 	flCPUSetMems := cmd.String([]string{"-cpuset-mems"}, "", "MEMs in which to allow execution (0-3, 0,1)")
 	flCgroupParent := cmd.String([]string{"-cgroup-parent"}, "", "Optional parent cgroup for the container")
+	flNetworkMode := cmd.String([]string{"-network"}, "default", "Set the networking mode for the RUN instructions during build")
+	flExtraHosts := opts.NewListOpts(runconfigopts.ValidateExtraHost)
+	cmd.Var(&flExtraHosts, []string{"-add-host"}, "Add a custom host-to-IP mapping (host:ip)")
 	flBuildArg := opts.NewListOpts(runconfigopts.ValidateEnv)
 	cmd.Var(&flBuildArg, []string{"-build-arg"}, "Set build-time variables")
 	isolation := cmd.String([]string{"-isolation"}, "", "Container isolation technology")
@@ -69,6 +75,9 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 	flUlimits := runconfigopts.NewUlimitOpt(&ulimits)
 	cmd.Var(flUlimits, []string{"-ulimit"}, "Ulimit options")
 
+	flOutput := cmd.String([]string{"-output"}, "", "Export the build result instead of tagging an image (type=tar|local,dest=<path>[,src=<path>])")
+	flDryRun := cmd.Bool([]string{"-dry-run"}, false, "Check the Dockerfile and the build context without running the build")
+
 	cmd.Require(flag.Exact, 1)
 
 	// For trusted pull on "FROM <image>" instruction.
@@ -91,8 +100,29 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 		buildBuff     io.Writer
 	)
 
-	progBuff = cli.out
-	buildBuff = cli.out
+	var output *buildOutput
+	if *flOutput != "" {
+		output, err = parseBuildOutput(*flOutput)
+		if err != nil {
+			return err
+		}
+		if len(flTags.GetAll()) > 0 {
+			return fmt.Errorf("Conflicting options: --output and --tag")
+		}
+		if *flDryRun {
+			return fmt.Errorf("Conflicting options: --output and --dry-run")
+		}
+	}
+
+	// When the exported archive is written to stdout, all other output
+	// goes to stderr.
+	stdout := cli.out
+	if output != nil && output.isStdout() {
+		stdout = cli.err
+	}
+
+	progBuff = stdout
+	buildBuff = stdout
 	if *suppressOutput {
 		progBuff = bytes.NewBuffer(nil)
 		buildBuff = bytes.NewBuffer(nil)
@@ -228,12 +258,18 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 		CPUQuota:       *flCPUQuota,
 		CPUPeriod:      *flCPUPeriod,
 		CgroupParent:   *flCgroupParent,
+		NetworkMode:    *flNetworkMode,
+		ExtraHosts:     flExtraHosts.GetAll(),
 		Dockerfile:     relDockerfile,
 		ShmSize:        shmSize,
 		Ulimits:        flUlimits.GetList(),
 		BuildArgs:      runconfigopts.ConvertKVStringsToMap(flBuildArg.GetAll()),
 		AuthConfigs:    cli.retrieveAuthConfigs(),
 		Labels:         runconfigopts.ConvertKVStringsToMap(flLabels.GetAll()),
+		DryRun:         *flDryRun,
+	}
+	if output != nil {
+		options.Output = output.src
 	}
 
 	response, err := cli.client.ImageBuild(context.Background(), options)
@@ -242,7 +278,47 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 	}
 	defer response.Body.Close()
 
-	err = jsonmessage.DisplayJSONMessagesStream(response.Body, buildBuff, cli.outFd, cli.isTerminalOut, nil)
+	var (
+		responseBody   io.Reader = response.Body
+		progressReader *io.PipeReader
+		exportDone     chan error
+	)
+	if output != nil {
+		// The daemon multiplexes the progress messages and the exported
+		// archive on the response body.
+		var progressWriter *io.PipeWriter
+		progressReader, progressWriter = io.Pipe()
+		archiveReader, archiveWriter := io.Pipe()
+		go func() {
+			_, err := stdcopy.StdCopy(archiveWriter, progressWriter, response.Body)
+			archiveWriter.CloseWithError(err)
+			progressWriter.CloseWithError(err)
+		}()
+		exportDone = make(chan error, 1)
+		go func() {
+			err := output.write(archiveReader, cli.out)
+			if err == nil {
+				// Consume any trailing padding so the progress messages
+				// that follow the archive are not blocked.
+				_, err = io.Copy(ioutil.Discard, archiveReader)
+			}
+			archiveReader.CloseWithError(err)
+			exportDone <- err
+		}()
+		responseBody = progressReader
+	}
+
+	err = jsonmessage.DisplayJSONMessagesStream(responseBody, buildBuff, cli.outFd, cli.isTerminalOut, nil)
+	if output != nil {
+		progressReader.Close()
+		if exportErr := <-exportDone; exportErr != nil {
+			// A failed build never sends the archive, so report the
+			// build error rather than the missing archive.
+			if _, ok := err.(*jsonmessage.JSONError); !ok {
+				return fmt.Errorf("Error exporting build output: %v", exportErr)
+			}
+		}
+	}
 	if err != nil {
 		if jerr, ok := err.(*jsonmessage.JSONError); ok {
 			// If no error code is set, default to 1
@@ -265,10 +341,10 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 	// Everything worked so if -q was provided the output from the daemon
 	// should be just the image ID and we'll print that to stdout.
 	if *suppressOutput {
-		fmt.Fprintf(cli.out, "%s", buildBuff)
+		fmt.Fprintf(stdout, "%s", buildBuff)
 	}
 
-	if isTrusted() {
+	if isTrusted() && !*flDryRun {
 		// Since the build was successful, now we must tag any of the resolved
 		// images from the above Dockerfile rewrite.
 		for _, resolved := range resolvedTags {
@@ -281,6 +357,94 @@ func (cli *DockerCli) CmdBuild(args ...string) error {
 	return nil
 }
 
+// buildOutput describes where the result of a build is exported to
+// instead of being tagged as an image.
+type buildOutput struct {
+	// typ is either "tar" to write a tar archive or "local" to extract
+	// the archive into a directory.
+	typ string
+	// src is the path inside the resulting filesystem to export.
+	src string
+	// dest is the path on the client to export to. "-" writes a tar
+	// archive to stdout.
+	dest string
+}
+
+// parseBuildOutput parses the value of the --output flag, which has the
+// form "type=tar|local,dest=<path>[,src=<path>]".
+func parseBuildOutput(value string) (*buildOutput, error) {
+	output := &buildOutput{src: "/"}
+	for _, field := range strings.Split(value, ",") {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return nil, fmt.Errorf("invalid output option %q, must be in the form key=value", field)
+		}
+		switch strings.ToLower(kv[0]) {
+		case "type":
+			output.typ = kv[1]
+		case "dest":
+			output.dest = kv[1]
+		case "src":
+			output.src = kv[1]
+		default:
+			return nil, fmt.Errorf("unknown output option %q", kv[0])
+		}
+	}
+
+	switch output.typ {
+	case "tar", "local":
+	case "":
+		return nil, fmt.Errorf("output type is required, must be one of tar or local")
+	default:
+		return nil, fmt.Errorf("invalid output type %q, must be one of tar or local", output.typ)
+	}
+	if output.dest == "" {
+		return nil, fmt.Errorf("output destination is required")
+	}
+	if output.typ == "local" && output.dest == "-" {
+		return nil, fmt.Errorf("output of type local cannot be written to stdout")
+	}
+	return output, nil
+}
+
+// isStdout returns whether the exported archive is written to stdout.
+func (o *buildOutput) isStdout() bool {
+	return o.typ == "tar" && o.dest == "-"
+}
+
+// write writes the exported archive read from r to the destination of the
+// output.
+func (o *buildOutput) write(r io.Reader, stdout io.Writer) error {
+	// Wait for the archive to start before touching the destination, so
+	// that nothing is created when the build fails.
+	br := bufio.NewReader(r)
+	if _, err := br.Peek(1); err != nil {
+		return err
+	}
+	r = br
+
+	if o.typ == "local" {
+		if err := os.MkdirAll(o.dest, 0755); err != nil {
+			return err
+		}
+		return archive.Untar(r, o.dest, &archive.TarOptions{NoLchown: true})
+	}
+
+	if o.isStdout() {
+		_, err := io.Copy(stdout, r)
+		return err
+	}
+	f, err := os.Create(o.dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
 // validateTag checks if the given image name can be resolved.
 func validateTag(rawRepo string) (string, error) {
 	_, err := reference.ParseNamed(rawRepo)
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"testing"
+)
+
+func TestParseBuildOutput(t *testing.T) {
+	valids := map[string]buildOutput{
+		"type=tar,dest=out.tar":              {typ: "tar", src: "/", dest: "out.tar"},
+		"type=tar,dest=-":                    {typ: "tar", src: "/", dest: "-"},
+		"type=local,dest=out":                {typ: "local", src: "/", dest: "out"},
+		"dest=out,type=local,src=/app/bin":   {typ: "local", src: "/app/bin", dest: "out"},
+		"TYPE=tar,Dest=out.tar,SRC=/app/bin": {typ: "tar", src: "/app/bin", dest: "out.tar"},
+	}
+	for value, expected := range valids {
+		output, err := parseBuildOutput(value)
+		if err != nil {
+			t.Fatalf("Expected %q to be valid, got error: %v", value, err)
+		}
+		if *output != expected {
+			t.Fatalf("Expected %q to be parsed as %+v, got %+v", value, expected, *output)
+		}
+	}
+
+	invalids := []string{
+		"",
+		"tar",
+		"type=tar",
+		"type=,dest=out",
+		"dest=out.tar",
+		"type=image,dest=out",
+		"type=local,dest=-",
+		"type=tar,dest=out,compression=gzip",
+	}
+	for _, value := range invalids {
+		if _, err := parseBuildOutput(value); err == nil {
+			t.Fatalf("Expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestBuildOutputIsStdout(t *testing.T) {
+	if !(&buildOutput{typ: "tar", dest: "-"}).isStdout() {
+		t.Fatal("Expected a tar output with destination - to be written to stdout")
+	}
+	if (&buildOutput{typ: "tar", dest: "out.tar"}).isStdout() {
+		t.Fatal("Expected a tar output with a file destination not to be written to stdout")
+	}
+}
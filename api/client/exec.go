@@ -8,8 +8,10 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/pkg/promise"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
 	"github.com/docker/engine-api/types"
 )
 
@@ -127,9 +129,12 @@ func ParseExec(cmd *flag.FlagSet, args []string) (*types.ExecConfig, error) {
 		flDetach     = cmd.Bool([]string{"d", "-detach"}, false, "Detached mode: run command in the background")
 		flUser       = cmd.String([]string{"u", "-user"}, "", "Username or UID (format: <name|uid>[:<group|gid>])")
 		flPrivileged = cmd.Bool([]string{"-privileged"}, false, "Give extended privileges to the command")
+		flWorkingDir = cmd.String([]string{"w", "-workdir"}, "", "Working directory inside the container")
+		flEnv        = opts.NewListOpts(runconfigopts.ValidateEnv)
 		execCmd      []string
 		container    string
 	)
+	cmd.Var(&flEnv, []string{"e", "-env"}, "Set environment variables")
 	cmd.Require(flag.Min, 2)
 	if err := cmd.ParseFlags(args, true); err != nil {
 		return nil, err
@@ -145,6 +150,8 @@ func ParseExec(cmd *flag.FlagSet, args []string) (*types.ExecConfig, error) {
 		Cmd:        execCmd,
 		Container:  container,
 		Detach:     *flDetach,
+		Env:        flEnv.GetAll(),
+		WorkingDir: *flWorkingDir,
 	}
 
 	// If -d is not set, attach to everything by default
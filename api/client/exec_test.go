@@ -18,6 +18,8 @@ func TestParseExec(t *testing.T) {
 		&arguments{[]string{"-unknown"}}: fmt.Errorf("flag provided but not defined: -unknown"),
 		&arguments{[]string{"-u"}}:       fmt.Errorf("flag needs an argument: -u"),
 		&arguments{[]string{"--user"}}:   fmt.Errorf("flag needs an argument: --user"),
+		&arguments{[]string{"-w"}}:       fmt.Errorf("flag needs an argument: -w"),
+		&arguments{[]string{"-e"}}:       fmt.Errorf("flag needs an argument: -e"),
 	}
 	valids := map[*arguments]*types.ExecConfig{
 		&arguments{
@@ -47,6 +49,16 @@ func TestParseExec(t *testing.T) {
 			Container:    "container",
 			Cmd:          []string{"command"},
 		},
+		&arguments{
+			[]string{"-e", "FOO=bar", "--env", "BAZ=qux", "-w", "/tmp", "container", "command"},
+		}: {
+			Env:          []string{"FOO=bar", "BAZ=qux"},
+			WorkingDir:   "/tmp",
+			AttachStdout: true,
+			AttachStderr: true,
+			Container:    "container",
+			Cmd:          []string{"command"},
+		},
 		&arguments{
 			[]string{"-d", "container", "command"},
 		}: {
@@ -118,6 +130,17 @@ func compareExecConfig(config1 *types.ExecConfig, config2 *types.ExecConfig) boo
 	if config1.User != config2.User {
 		return false
 	}
+	if config1.WorkingDir != config2.WorkingDir {
+		return false
+	}
+	if len(config1.Env) != len(config2.Env) {
+		return false
+	}
+	for index, value := range config1.Env {
+		if value != config2.Env[index] {
+			return false
+		}
+	}
 	if len(config1.Cmd) != len(config2.Cmd) {
 		return false
 	}
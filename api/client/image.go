@@ -0,0 +1,189 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"golang.org/x/net/context"
+
+	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/opts"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/pkg/stringid"
+	"github.com/docker/docker/reference"
+	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
+)
+
+// CmdImage is the parent subcommand for all image commands
+//
+// Usage: docker image <COMMAND> <OPTS>
+func (cli *DockerCli) CmdImage(args ...string) error {
+	description := Cli.DockerCommands["image"].Description + "\n\nCommands:\n"
+	commands := [][]string{
+		{"config", "Create an image with a modified config"},
+		{"verify", "Verify the integrity of image layers"},
+	}
+
+	for _, cmd := range commands {
+		description += fmt.Sprintf("  %-25.25s%s\n", cmd[0], cmd[1])
+	}
+
+	description += "\nRun 'docker image COMMAND --help' for more information on a command"
+	cmd := Cli.Subcmd("image", []string{"[COMMAND]"}, description, false)
+
+	cmd.Require(flag.Exact, 0)
+	err := cmd.ParseFlags(args, true)
+	cmd.Usage()
+	return err
+}
+
+// CmdImageConfig creates a new image with the layers of an image and its
+// config modified by Dockerfile instructions, without running a container.
+//
+// Usage: docker image config [OPTIONS] IMAGE
+func (cli *DockerCli) CmdImageConfig(args ...string) error {
+	cmd := Cli.Subcmd("image config", []string{"IMAGE"}, "Create an image with the layers of an image and a modified config", true)
+	flTag := cmd.String([]string{"t", "-tag"}, "", "Name and optionally a tag in the 'name:tag' format")
+	flComment := cmd.String([]string{"m", "-message"}, "", "Commit message")
+	flAuthor := cmd.String([]string{"a", "-author"}, "", "Author (e.g., \"John Hannibal Smith <hannibal@a-team.com>\")")
+	flEntrypoint := cmd.String([]string{"-entrypoint"}, "", "Set the entrypoint, in the format of the ENTRYPOINT instruction")
+	flCmd := cmd.String([]string{"-cmd"}, "", "Set the command, in the format of the CMD instruction")
+	flChanges := opts.NewListOpts(nil)
+	cmd.Var(&flChanges, []string{"c", "-change"}, "Apply Dockerfile instruction to the created image")
+	flEnv := opts.NewListOpts(runconfigopts.ValidateEnv)
+	cmd.Var(&flEnv, []string{"e", "-env"}, "Set environment variables")
+	flLabels := opts.NewListOpts(opts.ValidateLabel)
+	cmd.Var(&flLabels, []string{"l", "-label"}, "Set metadata on the image")
+	flExpose := opts.NewListOpts(nil)
+	cmd.Var(&flExpose, []string{"-expose"}, "Expose a port or a range of ports")
+	cmd.Require(flag.Exact, 1)
+
+	cmd.ParseFlags(args, true)
+
+	var repositoryName, tag string
+	if *flTag != "" {
+		ref, err := reference.ParseNamed(*flTag)
+		if err != nil {
+			return err
+		}
+
+		repositoryName = ref.Name()
+
+		switch x := ref.(type) {
+		case reference.Canonical:
+			return errors.New("cannot tag an image with a digest reference")
+		case reference.NamedTagged:
+			tag = x.Tag()
+		}
+	}
+
+	changes := flChanges.GetAll()
+	changes = append(changes, keyValueChanges("ENV", flEnv.GetAll())...)
+	changes = append(changes, keyValueChanges("LABEL", flLabels.GetAll())...)
+	for _, port := range flExpose.GetAll() {
+		changes = append(changes, "EXPOSE "+port)
+	}
+	if cmd.IsSet("-entrypoint") {
+		changes = append(changes, instructionChange("ENTRYPOINT", *flEntrypoint))
+	}
+	if cmd.IsSet("-cmd") {
+		changes = append(changes, instructionChange("CMD", *flCmd))
+	}
+
+	options := types.ImageConfigOptions{
+		ImageID:        cmd.Arg(0),
+		RepositoryName: repositoryName,
+		Tag:            tag,
+		Comment:        *flComment,
+		Author:         *flAuthor,
+		Changes:        changes,
+	}
+
+	response, err := cli.client.ImageConfig(context.Background(), options)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cli.out, response.ID)
+	return nil
+}
+
+// keyValueChanges returns an instruction setting each key=value pair of
+// pairs, such as ENV or LABEL. Keys and values are quoted so that they are
+// set as given.
+func keyValueChanges(instruction string, pairs []string) []string {
+	var changes []string
+	for _, pair := range pairs {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) == 1 {
+			// Let the daemon refuse a key without a value
+			changes = append(changes, instruction+" "+quoteChangeWord(kv[0]))
+			continue
+		}
+		changes = append(changes, fmt.Sprintf("%s %s=%s", instruction, quoteChangeWord(kv[0]), quoteChangeWord(kv[1])))
+	}
+	return changes
+}
+
+// instructionChange returns the instruction with args, which are in the
+// format of the instruction in a Dockerfile. Empty args reset the
+// instruction.
+func instructionChange(instruction, args string) string {
+	if args == "" {
+		args = "[]"
+	}
+	return instruction + " " + args
+}
+
+// quoteChangeWord quotes s for a Dockerfile instruction, escaping the
+// characters which would otherwise be interpreted.
+func quoteChangeWord(s string) string {
+	s = strings.Replace(s, `"`, `\"`, -1)
+	s = strings.Replace(s, "$", `\$`, -1)
+	return `"` + s + `"`
+}
+
+// CmdImageVerify verifies the integrity of the layers of images, or of all
+// images if none is given, and lists the corrupt layers.
+//
+// Usage: docker image verify [OPTIONS] [IMAGE...]
+func (cli *DockerCli) CmdImageVerify(args ...string) error {
+	cmd := Cli.Subcmd("image verify", []string{"[IMAGE...]"}, "Verify the integrity of the layers of images, or of all images", true)
+	noTrunc := cmd.Bool([]string{"-no-trunc"}, false, "Don't truncate output")
+
+	cmd.ParseFlags(args, true)
+
+	report, err := cli.client.ImageVerify(context.Background(), cmd.Args())
+	if err != nil {
+		return err
+	}
+
+	if len(report.Corrupt) == 0 {
+		fmt.Fprintf(cli.out, "Verified %d layers, none is corrupt\n", report.Layers)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(cli.out, 20, 1, 3, ' ', 0)
+	fmt.Fprintln(w, "LAYER\tIMAGES\tERROR")
+	for _, c := range report.Corrupt {
+		layerID := c.ChainID
+		images := c.Images
+		if !*noTrunc {
+			layerID = stringid.TruncateID(layerID)
+			images = make([]string, len(c.Images))
+			for i, id := range c.Images {
+				images[i] = stringid.TruncateID(id)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", layerID, strings.Join(images, ", "), c.Error)
+	}
+	w.Flush()
+
+	return Cli.StatusError{
+		Status:     fmt.Sprintf("%d of %d layers are corrupt", len(report.Corrupt), report.Layers),
+		StatusCode: 1,
+	}
+}
@@ -0,0 +1,45 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/docker/docker/builder/dockerfile"
+	"github.com/docker/engine-api/types/container"
+)
+
+func TestImageConfigChanges(t *testing.T) {
+	changes := keyValueChanges("ENV", []string{"PATH=/bin:$PATH", `GREETING=say "hello world"`})
+	changes = append(changes, keyValueChanges("LABEL", []string{"com.example.vendor=ACME Inc.", "empty="})...)
+	changes = append(changes, instructionChange("ENTRYPOINT", `["/bin/app", "--verbose"]`))
+	changes = append(changes, instructionChange("CMD", ""))
+
+	config, err := dockerfile.BuildFromConfig(&container.Config{
+		Env: []string{"PATH=/usr/bin"},
+		Cmd: []string{"sh"},
+	}, changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedEnv := []string{"PATH=/bin:$PATH", `GREETING=say "hello world"`}
+	if len(config.Env) != len(expectedEnv) {
+		t.Fatalf("Expected env %q, got %q", expectedEnv, config.Env)
+	}
+	for i := range expectedEnv {
+		if config.Env[i] != expectedEnv[i] {
+			t.Fatalf("Expected env %q, got %q", expectedEnv, config.Env)
+		}
+	}
+	if config.Labels["com.example.vendor"] != "ACME Inc." {
+		t.Fatalf("Expected label %q, got %q", "ACME Inc.", config.Labels["com.example.vendor"])
+	}
+	if v, ok := config.Labels["empty"]; !ok || v != "" {
+		t.Fatalf("Expected an empty label, got %v", config.Labels)
+	}
+	if len(config.Entrypoint) != 2 || config.Entrypoint[0] != "/bin/app" || config.Entrypoint[1] != "--verbose" {
+		t.Fatalf("Unexpected entrypoint %q", config.Entrypoint)
+	}
+	if len(config.Cmd) != 0 {
+		t.Fatalf("Expected the command to be reset, got %q", config.Cmd)
+	}
+}
@@ -2,6 +2,7 @@ package client
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	"golang.org/x/net/context"
@@ -68,6 +69,15 @@ func (cli *DockerCli) CmdInfo(args ...string) error {
 		fmt.Fprintf(cli.out, "\n")
 	}
 
+	if len(info.Runtimes) > 0 {
+		var runtimes []string
+		for name := range info.Runtimes {
+			runtimes = append(runtimes, name)
+		}
+		sort.Strings(runtimes)
+		fmt.Fprintf(cli.out, "Runtimes: %s\n", strings.Join(runtimes, " "))
+	}
+
 	ioutils.FprintfIfNotEmpty(cli.out, "Kernel Version: %s\n", info.KernelVersion)
 	ioutils.FprintfIfNotEmpty(cli.out, "Operating System: %s\n", info.OperatingSystem)
 	ioutils.FprintfIfNotEmpty(cli.out, "OSType: %s\n", info.OSType)
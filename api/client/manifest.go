@@ -0,0 +1,343 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/net/context"
+
+	"github.com/docker/distribution/manifest/manifestlist"
+	Cli "github.com/docker/docker/cli"
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/docker/distribution"
+	"github.com/docker/docker/opts"
+	flag "github.com/docker/docker/pkg/mflag"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	registrytypes "github.com/docker/engine-api/types/registry"
+)
+
+// localManifestList is a manifest list assembled locally by
+// `docker manifest create` and `docker manifest annotate`, before it is
+// pushed to a registry.
+type localManifestList struct {
+	Ref       string
+	Manifests []localManifestListEntry
+}
+
+// localManifestListEntry is a manifest referenced by a local manifest list.
+type localManifestListEntry struct {
+	Ref        string
+	Descriptor manifestlist.ManifestDescriptor
+}
+
+// add adds a manifest to the list. A manifest already in the list is
+// replaced.
+func (l *localManifestList) add(ref reference.Named, descriptor manifestlist.ManifestDescriptor) {
+	entry := localManifestListEntry{Ref: ref.String(), Descriptor: descriptor}
+	for i, e := range l.Manifests {
+		if e.Ref == entry.Ref || e.Descriptor.Digest == descriptor.Digest {
+			l.Manifests[i] = entry
+			return
+		}
+	}
+	l.Manifests = append(l.Manifests, entry)
+}
+
+// get returns the entry of the manifest ref refers to.
+func (l *localManifestList) get(ref reference.Named) (*localManifestListEntry, error) {
+	for i, e := range l.Manifests {
+		if e.Ref == ref.String() {
+			return &l.Manifests[i], nil
+		}
+		if digested, isDigested := ref.(reference.Canonical); isDigested && digested.Digest() == e.Descriptor.Digest {
+			return &l.Manifests[i], nil
+		}
+	}
+	return nil, fmt.Errorf("manifest %s is not in manifest list %s", ref.String(), l.Ref)
+}
+
+func manifestListDirectory() string {
+	return filepath.Join(cliconfig.ConfigDir(), "manifests")
+}
+
+func manifestListPath(ref reference.Named) string {
+	return filepath.Join(manifestListDirectory(), url.QueryEscape(ref.String()))
+}
+
+// loadManifestList reads the local manifest list ref refers to. The error
+// satisfies os.IsNotExist if there is none.
+func loadManifestList(ref reference.Named) (*localManifestList, error) {
+	data, err := ioutil.ReadFile(manifestListPath(ref))
+	if err != nil {
+		return nil, err
+	}
+	var list localManifestList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("invalid manifest list %s: %v", ref.String(), err)
+	}
+	return &list, nil
+}
+
+func saveManifestList(ref reference.Named, list *localManifestList) error {
+	if err := os.MkdirAll(manifestListDirectory(), 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(manifestListPath(ref), data, 0600)
+}
+
+// parseManifestListName parses the name of a manifest list, which is tagged
+// with the default tag if it has no tag.
+func parseManifestListName(name string) (reference.NamedTagged, error) {
+	ref, err := reference.ParseNamed(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, isDigested := ref.(reference.Canonical); isDigested {
+		return nil, errors.New("a manifest list cannot be referenced by digest")
+	}
+	return reference.WithDefaultTag(ref).(reference.NamedTagged), nil
+}
+
+// manifestListConfig returns the configuration for looking up manifests
+// in the registry of ref, or pushing manifest lists to it, using the
+// credentials of the configuration file. The client talks to the registries
+// itself, with the insecure registries and mirrors the daemon is configured
+// with.
+func (cli *DockerCli) manifestListConfig(ref reference.Named) (*distribution.ManifestListConfig, error) {
+	info, err := cli.client.Info(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	registryService := registry.NewService(serviceOptionsFromConfig(info.RegistryConfig))
+
+	repoInfo, err := registryService.ResolveRepository(ref)
+	if err != nil {
+		return nil, err
+	}
+	authConfig := cli.resolveAuthConfig(repoInfo.Index)
+	return &distribution.ManifestListConfig{
+		AuthConfig:      &authConfig,
+		RegistryService: registryService,
+	}, nil
+}
+
+// serviceOptionsFromConfig returns the options of a registry service
+// configured like the registry service of the daemon.
+func serviceOptionsFromConfig(config *registrytypes.ServiceConfig) registry.ServiceOptions {
+	var options registry.ServiceOptions
+	if config == nil {
+		return options
+	}
+	options.Mirrors = config.Mirrors
+	for _, ipnet := range config.InsecureRegistryCIDRs {
+		options.InsecureRegistries = append(options.InsecureRegistries, (*net.IPNet)(ipnet).String())
+	}
+	for name, index := range config.IndexConfigs {
+		if index.Official {
+			continue
+		}
+		if !index.Secure {
+			options.InsecureRegistries = append(options.InsecureRegistries, name)
+		}
+		if len(index.Mirrors) > 0 {
+			if options.RegistryMirrors == nil {
+				options.RegistryMirrors = make(map[string][]string)
+			}
+			options.RegistryMirrors[name] = index.Mirrors
+		}
+	}
+	return options
+}
+
+// CmdManifest is the parent subcommand for all manifest commands
+//
+// Usage: docker manifest <COMMAND> <OPTS>
+func (cli *DockerCli) CmdManifest(args ...string) error {
+	description := Cli.DockerCommands["manifest"].Description + "\n\nCommands:\n"
+	commands := [][]string{
+		{"create", "Create a local manifest list"},
+		{"annotate", "Set the platform of a manifest in a local manifest list"},
+		{"push", "Push a local manifest list to a registry"},
+	}
+
+	for _, cmd := range commands {
+		description += fmt.Sprintf("  %-25.25s%s\n", cmd[0], cmd[1])
+	}
+
+	description += "\nRun 'docker manifest COMMAND --help' for more information on a command"
+	cmd := Cli.Subcmd("manifest", []string{"[COMMAND]"}, description, false)
+
+	cmd.Require(flag.Exact, 0)
+	err := cmd.ParseFlags(args, true)
+	cmd.Usage()
+	return err
+}
+
+// CmdManifestCreate creates a local manifest list referencing manifests
+// in a registry.
+//
+// Usage: docker manifest create [OPTIONS] MANIFEST_LIST MANIFEST [MANIFEST...]
+func (cli *DockerCli) CmdManifestCreate(args ...string) error {
+	cmd := Cli.Subcmd("manifest create", []string{"MANIFEST_LIST MANIFEST [MANIFEST...]"}, "Create a local manifest list referencing manifests in a registry", true)
+	amend := cmd.Bool([]string{"a", "-amend"}, false, "Add the manifests to an existing local manifest list")
+	cmd.Require(flag.Min, 2)
+
+	cmd.ParseFlags(args, true)
+
+	listRef, err := parseManifestListName(cmd.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	list, err := loadManifestList(listRef)
+	switch {
+	case err == nil:
+		if !*amend {
+			return fmt.Errorf("manifest list %s already exists, use --amend to add manifests to it", listRef.String())
+		}
+	case os.IsNotExist(err):
+		list = &localManifestList{Ref: listRef.String()}
+	default:
+		return err
+	}
+
+	for _, name := range cmd.Args()[1:] {
+		ref, err := reference.ParseNamed(name)
+		if err != nil {
+			return err
+		}
+		ref = reference.WithDefaultTag(ref)
+
+		config, err := cli.manifestListConfig(ref)
+		if err != nil {
+			return err
+		}
+		descriptor, err := distribution.GetManifestDescriptor(context.Background(), ref, config)
+		if err != nil {
+			return fmt.Errorf("failed to look up manifest %s: %v", ref.String(), err)
+		}
+		list.add(ref, descriptor)
+	}
+
+	if err := saveManifestList(listRef, list); err != nil {
+		return err
+	}
+	fmt.Fprintf(cli.out, "Created manifest list %s\n", listRef.String())
+	return nil
+}
+
+// CmdManifestAnnotate sets the platform of a manifest in a local manifest
+// list.
+//
+// Usage: docker manifest annotate [OPTIONS] MANIFEST_LIST MANIFEST
+func (cli *DockerCli) CmdManifestAnnotate(args ...string) error {
+	cmd := Cli.Subcmd("manifest annotate", []string{"MANIFEST_LIST MANIFEST"}, "Set the platform of a manifest in a local manifest list", true)
+	flOS := cmd.String([]string{"-os"}, "", "Set the operating system")
+	flArch := cmd.String([]string{"-arch"}, "", "Set the architecture")
+	flVariant := cmd.String([]string{"-variant"}, "", "Set the architecture variant")
+	flOSFeatures := opts.NewListOpts(nil)
+	cmd.Var(&flOSFeatures, []string{"-os-features"}, "Set an operating system feature")
+	cmd.Require(flag.Exact, 2)
+
+	cmd.ParseFlags(args, true)
+
+	listRef, err := parseManifestListName(cmd.Arg(0))
+	if err != nil {
+		return err
+	}
+	ref, err := reference.ParseNamed(cmd.Arg(1))
+	if err != nil {
+		return err
+	}
+	ref = reference.WithDefaultTag(ref)
+
+	list, err := loadManifestList(listRef)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("No such manifest list: %s", listRef.String())
+		}
+		return err
+	}
+	entry, err := list.get(ref)
+	if err != nil {
+		return err
+	}
+
+	platform := &entry.Descriptor.Platform
+	if cmd.IsSet("-os") {
+		platform.OS = *flOS
+	}
+	if cmd.IsSet("-arch") {
+		platform.Architecture = *flArch
+	}
+	if cmd.IsSet("-variant") {
+		platform.Variant = *flVariant
+	}
+	if flOSFeatures.Len() > 0 {
+		platform.OSFeatures = flOSFeatures.GetAll()
+	}
+
+	return saveManifestList(listRef, list)
+}
+
+// CmdManifestPush pushes a local manifest list to a registry.
+//
+// Usage: docker manifest push [OPTIONS] MANIFEST_LIST
+func (cli *DockerCli) CmdManifestPush(args ...string) error {
+	cmd := Cli.Subcmd("manifest push", []string{"MANIFEST_LIST"}, "Push a local manifest list to a registry", true)
+	purge := cmd.Bool([]string{"p", "-purge"}, false, "Remove the local manifest list after pushing it")
+	cmd.Require(flag.Exact, 1)
+
+	cmd.ParseFlags(args, true)
+
+	listRef, err := parseManifestListName(cmd.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	list, err := loadManifestList(listRef)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("No such manifest list: %s", listRef.String())
+		}
+		return err
+	}
+	if len(list.Manifests) == 0 {
+		return fmt.Errorf("manifest list %s is empty", listRef.String())
+	}
+
+	entries := make([]distribution.ManifestListEntry, 0, len(list.Manifests))
+	for _, e := range list.Manifests {
+		ref, err := reference.ParseNamed(e.Ref)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, distribution.ManifestListEntry{Repository: ref, Descriptor: e.Descriptor})
+	}
+
+	config, err := cli.manifestListConfig(listRef)
+	if err != nil {
+		return err
+	}
+	dgst, err := distribution.PushManifestList(context.Background(), listRef, entries, config)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(cli.out, "%s: digest: %s\n", listRef.Tag(), dgst)
+
+	if *purge {
+		return os.Remove(manifestListPath(listRef))
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/cliconfig"
+	"github.com/docker/docker/reference"
+	registrytypes "github.com/docker/engine-api/types/registry"
+)
+
+func TestLocalManifestList(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "docker-manifest-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	configDir := cliconfig.ConfigDir()
+	cliconfig.SetConfigDir(tmpDir)
+	defer cliconfig.SetConfigDir(configDir)
+
+	listRef, err := parseManifestListName("myregistry.local:5000/app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if listRef.Tag() != reference.DefaultTag {
+		t.Fatalf("Expected the manifest list to have the default tag, got %s", listRef.Tag())
+	}
+	if _, err := parseManifestListName("app@" + digest.FromBytes([]byte("app")).String()); err == nil {
+		t.Fatal("Expected a manifest list referenced by digest to be invalid")
+	}
+
+	if _, err := loadManifestList(listRef); !os.IsNotExist(err) {
+		t.Fatalf("Expected no manifest list, got %v", err)
+	}
+
+	amd64, _ := reference.ParseNamed("myregistry.local:5000/app:amd64")
+	arm, _ := reference.ParseNamed("myregistry.local:5000/app:arm")
+	amd64Digest := digest.FromBytes([]byte("amd64"))
+	armDigest := digest.FromBytes([]byte("arm"))
+
+	list := &localManifestList{Ref: listRef.String()}
+	list.add(amd64, manifestlist.ManifestDescriptor{
+		Descriptor: distribution.Descriptor{Digest: amd64Digest},
+		Platform:   manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"},
+	})
+	list.add(arm, manifestlist.ManifestDescriptor{
+		Descriptor: distribution.Descriptor{Digest: digest.FromBytes([]byte("old"))},
+	})
+	list.add(arm, manifestlist.ManifestDescriptor{
+		Descriptor: distribution.Descriptor{Digest: armDigest},
+		Platform:   manifestlist.PlatformSpec{OS: "linux", Architecture: "arm"},
+	})
+	if len(list.Manifests) != 2 {
+		t.Fatalf("Expected adding a manifest again to replace it, got %d manifests", len(list.Manifests))
+	}
+
+	if err := saveManifestList(listRef, list); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := loadManifestList(listRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := loaded.get(arm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Descriptor.Digest != armDigest || entry.Descriptor.Platform.Architecture != "arm" {
+		t.Fatalf("Expected the arm manifest, got %+v", entry.Descriptor)
+	}
+	byDigest, _ := reference.WithDigest(arm, amd64Digest)
+	if entry, err = loaded.get(byDigest); err != nil || entry.Ref != amd64.String() {
+		t.Fatalf("Expected the amd64 manifest by digest, got %+v, %v", entry, err)
+	}
+	unknown, _ := reference.ParseNamed("myregistry.local:5000/app:ppc64le")
+	if _, err := loaded.get(unknown); err == nil {
+		t.Fatal("Expected a manifest not in the list not to be found")
+	}
+}
+
+func TestServiceOptionsFromConfig(t *testing.T) {
+	_, ipnet, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &registrytypes.ServiceConfig{
+		InsecureRegistryCIDRs: []*registrytypes.NetIPNet{(*registrytypes.NetIPNet)(ipnet)},
+		IndexConfigs: map[string]*registrytypes.IndexInfo{
+			"docker.io":             {Name: "docker.io", Mirrors: []string{"https://mirror.local"}, Secure: true, Official: true},
+			"myregistry.local:5000": {Name: "myregistry.local:5000", Mirrors: []string{"https://cache.local"}, Secure: false},
+		},
+		Mirrors: []string{"https://mirror.local"},
+	}
+
+	options := serviceOptionsFromConfig(config)
+	sort.Strings(options.InsecureRegistries)
+	if expected := []string{"10.0.0.0/8", "myregistry.local:5000"}; !reflect.DeepEqual(options.InsecureRegistries, expected) {
+		t.Fatalf("Expected insecure registries %v, got %v", expected, options.InsecureRegistries)
+	}
+	if !reflect.DeepEqual(options.Mirrors, config.Mirrors) {
+		t.Fatalf("Expected mirrors %v, got %v", config.Mirrors, options.Mirrors)
+	}
+	if mirrors := options.RegistryMirrors["myregistry.local:5000"]; len(mirrors) != 1 || mirrors[0] != "https://cache.local" {
+		t.Fatalf("Expected the mirrors of myregistry.local:5000, got %v", options.RegistryMirrors)
+	}
+
+	if options := serviceOptionsFromConfig(nil); len(options.InsecureRegistries) != 0 {
+		t.Fatalf("Expected no insecure registries, got %v", options.InsecureRegistries)
+	}
+}
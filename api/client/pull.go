@@ -13,6 +13,7 @@ import (
 	"github.com/docker/docker/registry"
 	"github.com/docker/engine-api/client"
 	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
 )
 
 // CmdPull pulls an image or a repository from the registry.
@@ -21,12 +22,19 @@ import (
 func (cli *DockerCli) CmdPull(args ...string) error {
 	cmd := Cli.Subcmd("pull", []string{"NAME[:TAG|@DIGEST]"}, Cli.DockerCommands["pull"].Description, true)
 	allTags := cmd.Bool([]string{"a", "-all-tags"}, false, "Download all tagged images in the repository")
+	platform := cmd.String([]string{"-platform"}, "", "Pull the image for a platform of a manifest list, in the form os/arch[/variant]")
+	flMaxBandwidth := cmd.String([]string{"-max-bandwidth"}, "", "Limit the bandwidth of the pull per second")
 	addTrustedFlags(cmd, true)
 	cmd.Require(flag.Exact, 1)
 
 	cmd.ParseFlags(args, true)
 	remote := cmd.Arg(0)
 
+	maxBandwidth, err := parseMaxBandwidth(*flMaxBandwidth)
+	if err != nil {
+		return err
+	}
+
 	distributionRef, err := reference.ParseNamed(remote)
 	if err != nil {
 		return err
@@ -61,13 +69,26 @@ func (cli *DockerCli) CmdPull(args ...string) error {
 
 	if isTrusted() && !ref.HasDigest() {
 		// Check if tag is digest
-		return cli.trustedPull(repoInfo, ref, authConfig, requestPrivilege)
+		return cli.trustedPull(repoInfo, ref, *platform, maxBandwidth, authConfig, requestPrivilege)
 	}
 
-	return cli.imagePullPrivileged(authConfig, distributionRef.String(), "", requestPrivilege)
+	return cli.imagePullPrivileged(authConfig, distributionRef.String(), "", *platform, maxBandwidth, requestPrivilege)
+}
+
+// parseMaxBandwidth parses the --max-bandwidth flag of pull and push, a size
+// per second such as 10MB. An empty value means there is no limit.
+func parseMaxBandwidth(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	maxBandwidth, err := units.FromHumanSize(value)
+	if err != nil || maxBandwidth <= 0 {
+		return 0, fmt.Errorf("invalid --max-bandwidth %q, must be a size per second such as 10MB", value)
+	}
+	return maxBandwidth, nil
 }
 
-func (cli *DockerCli) imagePullPrivileged(authConfig types.AuthConfig, imageID, tag string, requestPrivilege client.RequestPrivilegeFunc) error {
+func (cli *DockerCli) imagePullPrivileged(authConfig types.AuthConfig, imageID, tag, platform string, maxBandwidth int64, requestPrivilege client.RequestPrivilegeFunc) error {
 
 	encodedAuth, err := encodeAuthToBase64(authConfig)
 	if err != nil {
@@ -77,6 +98,8 @@ func (cli *DockerCli) imagePullPrivileged(authConfig types.AuthConfig, imageID,
 		ImageID:      imageID,
 		Tag:          tag,
 		RegistryAuth: encodedAuth,
+		Platform:     platform,
+		MaxBandwidth: maxBandwidth,
 	}
 
 	responseBody, err := cli.client.ImagePull(context.Background(), options, requestPrivilege)
@@ -20,11 +20,17 @@ import (
 // Usage: docker push NAME[:TAG]
 func (cli *DockerCli) CmdPush(args ...string) error {
 	cmd := Cli.Subcmd("push", []string{"NAME[:TAG]"}, Cli.DockerCommands["push"].Description, true)
+	flMaxBandwidth := cmd.String([]string{"-max-bandwidth"}, "", "Limit the bandwidth of the push per second")
 	addTrustedFlags(cmd, false)
 	cmd.Require(flag.Exact, 1)
 
 	cmd.ParseFlags(args, true)
 
+	maxBandwidth, err := parseMaxBandwidth(*flMaxBandwidth)
+	if err != nil {
+		return err
+	}
+
 	ref, err := reference.ParseNamed(cmd.Arg(0))
 	if err != nil {
 		return err
@@ -48,10 +54,10 @@ func (cli *DockerCli) CmdPush(args ...string) error {
 
 	requestPrivilege := cli.registryAuthenticationPrivilegedFunc(repoInfo.Index, "push")
 	if isTrusted() {
-		return cli.trustedPush(repoInfo, tag, authConfig, requestPrivilege)
+		return cli.trustedPush(repoInfo, tag, maxBandwidth, authConfig, requestPrivilege)
 	}
 
-	responseBody, err := cli.imagePushPrivileged(authConfig, ref.Name(), tag, requestPrivilege)
+	responseBody, err := cli.imagePushPrivileged(authConfig, ref.Name(), tag, maxBandwidth, requestPrivilege)
 	if err != nil {
 		return err
 	}
@@ -61,7 +67,7 @@ func (cli *DockerCli) CmdPush(args ...string) error {
 	return jsonmessage.DisplayJSONMessagesStream(responseBody, cli.out, cli.outFd, cli.isTerminalOut, nil)
 }
 
-func (cli *DockerCli) imagePushPrivileged(authConfig types.AuthConfig, imageID, tag string, requestPrivilege client.RequestPrivilegeFunc) (io.ReadCloser, error) {
+func (cli *DockerCli) imagePushPrivileged(authConfig types.AuthConfig, imageID, tag string, maxBandwidth int64, requestPrivilege client.RequestPrivilegeFunc) (io.ReadCloser, error) {
 	encodedAuth, err := encodeAuthToBase64(authConfig)
 	if err != nil {
 		return nil, err
@@ -70,6 +76,7 @@ func (cli *DockerCli) imagePushPrivileged(authConfig types.AuthConfig, imageID,
 		ImageID:      imageID,
 		Tag:          tag,
 		RegistryAuth: encodedAuth,
+		MaxBandwidth: maxBandwidth,
 	}
 
 	return cli.client.ImagePush(context.Background(), options, requestPrivilege)
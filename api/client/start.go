@@ -53,6 +53,7 @@ func (cli *DockerCli) CmdStart(args ...string) error {
 	attach := cmd.Bool([]string{"a", "-attach"}, false, "Attach STDOUT/STDERR and forward signals")
 	openStdin := cmd.Bool([]string{"i", "-interactive"}, false, "Attach container's STDIN")
 	detachKeys := cmd.String([]string{"-detach-keys"}, "", "Override the key sequence for detaching a container")
+	checkpoint := cmd.String([]string{"-checkpoint"}, "", "Restore from this checkpoint")
 	cmd.Require(flag.Min, 1)
 
 	cmd.ParseFlags(args, true)
@@ -113,7 +114,10 @@ func (cli *DockerCli) CmdStart(args ...string) error {
 		})
 
 		// 3. Start the container.
-		if err := cli.client.ContainerStart(context.Background(), containerID); err != nil {
+		startOptions := types.ContainerStartOptions{
+			CheckpointID: *checkpoint,
+		}
+		if err := cli.client.ContainerStart(context.Background(), containerID, startOptions); err != nil {
 			cancelFun()
 			<-cErr
 			return err
@@ -135,6 +139,20 @@ func (cli *DockerCli) CmdStart(args ...string) error {
 		if status != 0 {
 			return Cli.StatusError{StatusCode: status}
 		}
+	} else if *checkpoint != "" {
+		// We're restoring a container from a checkpoint.
+		if cmd.NArg() > 1 {
+			return fmt.Errorf("You cannot restore multiple containers at once.")
+		}
+
+		containerID := cmd.Arg(0)
+		startOptions := types.ContainerStartOptions{
+			CheckpointID: *checkpoint,
+		}
+		if err := cli.client.ContainerStart(context.Background(), containerID, startOptions); err != nil {
+			return err
+		}
+		fmt.Fprintf(cli.out, "%s\n", containerID)
 	} else {
 		// We're not going to attach to anything.
 		// Start as many containers as we want.
@@ -147,7 +165,7 @@ func (cli *DockerCli) CmdStart(args ...string) error {
 func (cli *DockerCli) startContainersWithoutAttachments(containerIDs []string) error {
 	var failedContainers []string
 	for _, containerID := range containerIDs {
-		if err := cli.client.ContainerStart(context.Background(), containerID); err != nil {
+		if err := cli.client.ContainerStart(context.Background(), containerID, types.ContainerStartOptions{}); err != nil {
 			fmt.Fprintf(cli.err, "%s\n", err)
 			failedContainers = append(failedContainers, containerID)
 		} else {
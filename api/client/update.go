@@ -29,6 +29,10 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 	flMemorySwap := cmd.String([]string{"-memory-swap"}, "", "Swap limit equal to memory plus swap: '-1' to enable unlimited swap")
 	flKernelMemory := cmd.String([]string{"-kernel-memory"}, "", "Kernel memory limit")
 	flRestartPolicy := cmd.String([]string{"-restart"}, "", "Restart policy to apply when a container exits")
+	flRestartMaxDelay := cmd.Duration([]string{"-restart-max-delay"}, 0, "Maximum delay between restarts of the container")
+	flRestartResetAfter := cmd.Duration([]string{"-restart-reset-after"}, 0, "Reset the delay between restarts once the container ran this long")
+	flRestartWindow := cmd.Duration([]string{"-restart-window"}, 0, "Window in which the restarts of the container are limited by --restart-window-max")
+	flRestartWindowMax := cmd.Int([]string{"-restart-window-max"}, 0, "Maximum number of restarts of the container within --restart-window")
 
 	cmd.Require(flag.Min, 1)
 	cmd.ParseFlags(args, true)
@@ -80,6 +84,32 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 			return err
 		}
 	}
+	updateRestartPolicy := *flRestartPolicy != ""
+	for _, name := range []string{"-restart-max-delay", "-restart-reset-after", "-restart-window", "-restart-window-max"} {
+		if cmd.IsSet(name) {
+			updateRestartPolicy = true
+		}
+	}
+	// The restart policy is sent as a whole, with the options which aren't
+	// given set to their current value, so options can be set back to 0.
+	setRestartPolicy := func(p *container.RestartPolicy) {
+		if *flRestartPolicy != "" {
+			p.Name = restartPolicy.Name
+			p.MaximumRetryCount = restartPolicy.MaximumRetryCount
+		}
+		if cmd.IsSet("-restart-max-delay") {
+			p.MaxDelay = *flRestartMaxDelay
+		}
+		if cmd.IsSet("-restart-reset-after") {
+			p.ResetAfter = *flRestartResetAfter
+		}
+		if cmd.IsSet("-restart-window") {
+			p.Window = *flRestartWindow
+		}
+		if cmd.IsSet("-restart-window-max") {
+			p.MaxRestartsInWindow = *flRestartWindowMax
+		}
+	}
 
 	resources := container.Resources{
 		BlkioWeight:       *flBlkioWeight,
@@ -94,14 +124,21 @@ func (cli *DockerCli) CmdUpdate(args ...string) error {
 		CPUQuota:          *flCPUQuota,
 	}
 
-	updateConfig := container.UpdateConfig{
-		Resources:     resources,
-		RestartPolicy: restartPolicy,
-	}
-
 	names := cmd.Args()
 	var errs []string
 	for _, name := range names {
+		updateConfig := container.UpdateConfig{
+			Resources: resources,
+		}
+		if updateRestartPolicy {
+			c, err := cli.client.ContainerInspect(context.Background(), name)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			updateConfig.RestartPolicy = c.HostConfig.RestartPolicy
+			setRestartPolicy(&updateConfig.RestartPolicy)
+		}
 		if err := cli.client.ContainerUpdate(context.Background(), name, updateConfig); err != nil {
 			errs = append(errs, err.Error())
 		} else {
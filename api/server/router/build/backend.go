@@ -17,4 +17,7 @@ type Backend interface {
 	//
 	// TODO: make this return a reference instead of string
 	BuildFromContext(ctx context.Context, src io.ReadCloser, remote string, buildOptions *types.ImageBuildOptions, pg backend.ProgressWriter) (string, error)
+	// ExportImagePath writes a tar archive of the filesystem resource at
+	// path inside the image referenced by imageID to out.
+	ExportImagePath(imageID string, path string, out io.Writer) error
 }
@@ -16,6 +16,7 @@ import (
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/stdcopy"
 	"github.com/docker/docker/pkg/streamformatter"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/container"
@@ -51,6 +52,25 @@ func newImageBuildOptions(ctx context.Context, r *http.Request) (*types.ImageBui
 	options.CgroupParent = r.FormValue("cgroupparent")
 	options.Tags = r.Form["t"]
 
+	if version.GreaterThanOrEqualTo("1.24") {
+		options.NetworkMode = r.FormValue("networkmode")
+		options.ExtraHosts = r.Form["extrahosts"]
+	}
+
+	if output := r.FormValue("output"); output != "" && version.GreaterThanOrEqualTo("1.24") {
+		if len(options.Tags) > 0 {
+			return nil, fmt.Errorf("Cannot tag an image when exporting the build output")
+		}
+		options.Output = output
+	}
+
+	if httputils.BoolValue(r, "dryrun") && version.GreaterThanOrEqualTo("1.24") {
+		if options.Output != "" {
+			return nil, fmt.Errorf("Cannot export the build output in a dry run")
+		}
+		options.DryRun = true
+	}
+
 	if r.Form.Get("shmsize") != "" {
 		shmSize, err := strconv.ParseInt(r.Form.Get("shmsize"), 10, 64)
 		if err != nil {
@@ -123,46 +143,60 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 		}
 	}
 
-	w.Header().Set("Content-Type", "application/json")
+	buildOptions, err := newImageBuildOptions(ctx, r)
+	if err != nil {
+		return err
+	}
+	buildOptions.AuthConfigs = authConfigs
 
 	output := ioutils.NewWriteFlusher(w)
 	defer output.Close()
+
+	// When the build output is exported, the progress messages and the
+	// exported archive share the response body, so both are multiplexed
+	// the same way as the stdout and stderr of an attached container.
+	var (
+		progressOutput io.Writer = output
+		exportOutput   io.Writer
+	)
+	if buildOptions.Output != "" {
+		w.Header().Set("Content-Type", "application/vnd.docker.raw-stream")
+		progressOutput = stdcopy.NewStdWriter(output, stdcopy.Stderr)
+		exportOutput = stdcopy.NewStdWriter(output, stdcopy.Stdout)
+	} else {
+		w.Header().Set("Content-Type", "application/json")
+	}
+
 	sf := streamformatter.NewJSONStreamFormatter()
 	errf := func(err error) error {
 		if httputils.BoolValue(r, "q") && notVerboseBuffer.Len() > 0 {
-			output.Write(notVerboseBuffer.Bytes())
+			progressOutput.Write(notVerboseBuffer.Bytes())
 		}
 		// Do not write the error in the http output if it's still empty.
 		// This prevents from writing a 200(OK) when there is an internal error.
 		if !output.Flushed() {
 			return err
 		}
-		_, err = w.Write(sf.FormatError(err))
+		_, err = progressOutput.Write(sf.FormatError(err))
 		if err != nil {
 			logrus.Warnf("could not write error response: %v", err)
 		}
 		return nil
 	}
 
-	buildOptions, err := newImageBuildOptions(ctx, r)
-	if err != nil {
-		return errf(err)
-	}
-	buildOptions.AuthConfigs = authConfigs
-
 	remoteURL := r.FormValue("remote")
 
 	// Currently, only used if context is from a remote url.
 	// Look at code in DetectContextFromRemoteURL for more information.
 	createProgressReader := func(in io.ReadCloser) io.ReadCloser {
-		progressOutput := sf.NewProgressOutput(output, true)
+		progressReaderOutput := sf.NewProgressOutput(progressOutput, true)
 		if buildOptions.SuppressOutput {
-			progressOutput = sf.NewProgressOutput(notVerboseBuffer, true)
+			progressReaderOutput = sf.NewProgressOutput(notVerboseBuffer, true)
 		}
-		return progress.NewProgressReader(in, progressOutput, r.ContentLength, "Downloading context", remoteURL)
+		return progress.NewProgressReader(in, progressReaderOutput, r.ContentLength, "Downloading context", remoteURL)
 	}
 
-	var out io.Writer = output
+	var out io.Writer = progressOutput
 	if buildOptions.SuppressOutput {
 		out = notVerboseBuffer
 	}
@@ -182,10 +216,17 @@ func (br *buildRouter) postBuild(ctx context.Context, w http.ResponseWriter, r *
 		return errf(err)
 	}
 
+	if buildOptions.Output != "" {
+		if err := br.backend.ExportImagePath(imgID, buildOptions.Output, exportOutput); err != nil {
+			return errf(err)
+		}
+	}
+
 	// Everything worked so if -q was provided the output from the daemon
-	// should be just the image ID and we'll print that to stdout.
-	if buildOptions.SuppressOutput {
-		stdout := &streamformatter.StdoutFormatter{Writer: output, StreamFormatter: sf}
+	// should be just the image ID and we'll print that to stdout. A dry
+	// run doesn't create an image, and an exported build removes it.
+	if buildOptions.SuppressOutput && !buildOptions.DryRun && buildOptions.Output == "" {
+		stdout := &streamformatter.StdoutFormatter{Writer: progressOutput, StreamFormatter: sf}
 		fmt.Fprintf(stdout, "%s\n", string(imgID))
 	}
 
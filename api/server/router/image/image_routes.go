@@ -104,7 +104,19 @@ func (s *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrite
 			}
 		}
 
-		err = s.backend.PullImage(ctx, image, tag, metaHeaders, authConfig, output)
+		var (
+			platform     string
+			maxBandwidth int64
+		)
+		if version := httputils.VersionFromContext(ctx); version.GreaterThanOrEqualTo("1.24") {
+			platform = r.Form.Get("platform")
+			maxBandwidth, err = parseMaxBandwidth(r)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = s.backend.PullImage(ctx, image, tag, platform, maxBandwidth, metaHeaders, authConfig, output)
 
 		// Check the error from pulling an image to make sure the request
 		// was authorized. Modify the status if the request was
@@ -130,6 +142,16 @@ func (s *imageRouter) postImagesCreate(ctx context.Context, w http.ResponseWrite
 	return nil
 }
 
+// parseMaxBandwidth parses the bandwidth limit of a pull or push, in bytes
+// per second.
+func parseMaxBandwidth(r *http.Request) (int64, error) {
+	maxBandwidth, err := httputils.Int64ValueOrDefault(r, "maxbandwidth", 0)
+	if err != nil || maxBandwidth < 0 {
+		return 0, fmt.Errorf("invalid maxbandwidth %q, must be a number of bytes per second", r.Form.Get("maxbandwidth"))
+	}
+	return maxBandwidth, nil
+}
+
 func (s *imageRouter) postImagesPush(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	metaHeaders := map[string][]string{}
 	for k, v := range r.Header {
@@ -160,12 +182,21 @@ func (s *imageRouter) postImagesPush(ctx context.Context, w http.ResponseWriter,
 	image := vars["name"]
 	tag := r.Form.Get("tag")
 
+	var maxBandwidth int64
+	if version := httputils.VersionFromContext(ctx); version.GreaterThanOrEqualTo("1.24") {
+		var err error
+		maxBandwidth, err = parseMaxBandwidth(r)
+		if err != nil {
+			return err
+		}
+	}
+
 	output := ioutils.NewWriteFlusher(w)
 	defer output.Close()
 
 	w.Header().Set("Content-Type", "application/json")
 
-	if err := s.backend.PushImage(ctx, image, tag, metaHeaders, authConfig, output); err != nil {
+	if err := s.backend.PushImage(ctx, image, tag, maxBandwidth, metaHeaders, authConfig, output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
@@ -191,7 +222,16 @@ func (s *imageRouter) getImagesGet(ctx context.Context, w http.ResponseWriter, r
 		names = r.Form["names"]
 	}
 
-	if err := s.backend.ExportImage(names, output); err != nil {
+	var (
+		format   string
+		excludes []string
+	)
+	if version := httputils.VersionFromContext(ctx); version.GreaterThanOrEqualTo("1.24") {
+		format = r.Form.Get("format")
+		excludes = r.Form["exclude"]
+	}
+
+	if err := s.backend.ExportImage(names, excludes, format, output); err != nil {
 		if !output.Flushed() {
 			return err
 		}
@@ -275,6 +315,18 @@ func (s *imageRouter) getImagesHistory(ctx context.Context, w http.ResponseWrite
 	return httputils.WriteJSON(w, http.StatusOK, history)
 }
 
+func (s *imageRouter) getImagesVerify(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+	report, err := s.backend.VerifyImages(r.Form["names"])
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusOK, report)
+}
+
 func (s *imageRouter) postImagesTag(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -286,6 +338,29 @@ func (s *imageRouter) postImagesTag(ctx context.Context, w http.ResponseWriter,
 	return nil
 }
 
+func (s *imageRouter) postImagesConfig(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
+	if err := httputils.ParseForm(r); err != nil {
+		return err
+	}
+
+	options := types.ImageConfigOptions{
+		RepositoryName: r.Form.Get("repo"),
+		Tag:            r.Form.Get("tag"),
+		Comment:        r.Form.Get("comment"),
+		Author:         r.Form.Get("author"),
+		Changes:        r.Form["changes"],
+	}
+
+	imgID, err := s.backend.ConfigImage(vars["name"], options)
+	if err != nil {
+		return err
+	}
+
+	return httputils.WriteJSON(w, http.StatusCreated, &types.ImageConfigResponse{
+		ID: imgID,
+	})
+}
+
 func (s *imageRouter) getImagesSearch(ctx context.Context, w http.ResponseWriter, r *http.Request, vars map[string]string) error {
 	if err := httputils.ParseForm(r); err != nil {
 		return err
@@ -124,7 +124,7 @@ type Backend interface {
 	// ContainerKill stops the container execution abruptly.
 	ContainerKill(containerID string, sig uint64) error
 	// ContainerStart starts a new container
-	ContainerStart(containerID string, hostConfig *container.HostConfig) error
+	ContainerStart(containerID string, hostConfig *container.HostConfig, checkpoint string) error
 	// ContainerWait stops processing until the given container is stopped.
 	ContainerWait(containerID string, timeout time.Duration) (int, error)
 	// ContainerUpdateCmdOnBuild updates container.Path and container.Args
@@ -137,7 +137,24 @@ type Backend interface {
 	// with Context.Walk
 	//ContainerCopy(name string, res string) (io.ReadCloser, error)
 	// TODO: use copyBackend api
-	CopyOnBuild(containerID string, destPath string, src FileInfo, decompress bool) error
+	CopyOnBuild(containerID string, destPath string, src FileInfo, decompress bool, options CopyOptions) error
+	// ContainerArchivePath creates an archive of the filesystem resource at
+	// the specified path in the container.
+	ContainerArchivePath(containerID string, path string) (io.ReadCloser, *types.ContainerPathStat, error)
+	// ImageDelete removes the image referenced by imageRef.
+	ImageDelete(imageRef string, force, prune bool) ([]types.ImageDelete, error)
+}
+
+// CopyOptions holds the options of a COPY or ADD instruction that are applied
+// to the files copied into a container.
+type CopyOptions struct {
+	// Chown is the "user[:group]" owning the copied files. User and group
+	// names are resolved against the /etc/passwd and /etc/group files of the
+	// container. When empty, the files are owned by root.
+	Chown string
+	// Chmod, if not nil, holds the permission bits replacing those of the
+	// copied files.
+	Chmod *uint32
 }
 
 // Image represents a Docker image used by the builder.
@@ -7,7 +7,9 @@ import (
 	"io"
 	"io/ioutil"
 	"os"
+	"runtime"
 	"strings"
+	"sync"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/api/types/backend"
@@ -18,6 +20,7 @@ import (
 	"github.com/docker/docker/reference"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/container"
+	"github.com/docker/engine-api/types/strslice"
 	"golang.org/x/net/context"
 )
 
@@ -63,13 +66,15 @@ type Builder struct {
 	runConfig        *container.Config // runconfig for cmd, run, entrypoint etc.
 	flags            *BFlags
 	tmpContainers    map[string]struct{}
-	image            string // imageID
+	image            string   // imageID
+	createdImages    []string // IDs of the images committed by this build
 	noBaseImage      bool
 	maintainer       string
 	cmdSet           bool
 	disableCommit    bool
 	cacheBusted      bool
 	allowedBuildArgs map[string]bool // list of build-time args that are allowed for expansion/substitution and passing to commands in 'run'.
+	warnings         int             // number of warnings reported by a dry run
 
 	// TODO: remove once docker.Commit can receive a tag
 	id string
@@ -78,11 +83,16 @@ type Builder struct {
 // BuildManager implements builder.Backend and is shared across all Builder objects.
 type BuildManager struct {
 	backend builder.Backend
+
+	mu sync.Mutex
+	// exports holds the images committed by the builds whose result is
+	// exported, by the ID of the resulting image.
+	exports map[string][]string
 }
 
 // NewBuildManager creates a BuildManager.
 func NewBuildManager(b builder.Backend) (bm *BuildManager) {
-	return &BuildManager{backend: b}
+	return &BuildManager{backend: b, exports: make(map[string][]string)}
 }
 
 // BuildFromContext builds a new image from a given context.
@@ -103,7 +113,71 @@ func (bm *BuildManager) BuildFromContext(ctx context.Context, src io.ReadCloser,
 	if err != nil {
 		return "", err
 	}
-	return b.build(pg.StdoutFormatter, pg.StderrFormatter, pg.Output)
+	imageID, err := b.build(pg.StdoutFormatter, pg.StderrFormatter, pg.Output)
+	if err != nil {
+		return "", err
+	}
+	if buildOptions.Output != "" {
+		bm.mu.Lock()
+		bm.exports[imageID] = b.createdImages
+		bm.mu.Unlock()
+	}
+	return imageID, nil
+}
+
+// ExportImagePath writes a tar archive of the filesystem resource at path
+// inside the image referenced by imageID to out. The filesystem of the image
+// is accessed through a temporary container that is never started and is
+// removed once the archive has been written. The images committed by the
+// build of imageID are removed as well, since the exported archive replaces
+// them. Images that existed before the build, such as the base image or the
+// images the build was cached from, are kept.
+func (bm *BuildManager) ExportImagePath(imageID string, path string, out io.Writer) error {
+	bm.mu.Lock()
+	created := bm.exports[imageID]
+	delete(bm.exports, imageID)
+	bm.mu.Unlock()
+
+	defer func() {
+		// Children are removed before their parents. An image is kept if
+		// something started to use it during the build, for example a
+		// container or a build that used it as cache.
+		for i := len(created) - 1; i >= 0; i-- {
+			if _, err := bm.backend.ImageDelete(created[i], false, false); err != nil {
+				logrus.Debugf("[BUILDER] failed to remove exported image %s: %v", created[i], err)
+			}
+		}
+	}()
+
+	config := &container.Config{Image: imageID}
+	if runtime.GOOS != "windows" {
+		config.Cmd = strslice.StrSlice{"/bin/sh", "-c", "#(nop) export " + path}
+	} else {
+		config.Cmd = strslice.StrSlice{"cmd", "/S /C", "REM (nop) export " + path}
+	}
+
+	c, err := bm.backend.ContainerCreate(types.ContainerCreateConfig{Config: config})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		rmConfig := &types.ContainerRmConfig{
+			ForceRemove:  true,
+			RemoveVolume: true,
+		}
+		if err := bm.backend.ContainerRm(c.ID, rmConfig); err != nil {
+			logrus.Debugf("[BUILDER] failed to remove export container %s: %v", c.ID, err)
+		}
+	}()
+
+	content, _, err := bm.backend.ContainerArchivePath(c.ID, path)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	_, err = io.Copy(out, content)
+	return err
 }
 
 // NewBuilder creates a new Dockerfile builder from an optional dockerfile and a Config.
@@ -212,6 +286,10 @@ func (b *Builder) build(stdout io.Writer, stderr io.Writer, out io.Writer) (stri
 		return "", err
 	}
 
+	if b.options.DryRun {
+		return "", b.check()
+	}
+
 	var shortImgID string
 	for i, n := range b.dockerfile.Children {
 		// we only want to add labels to the last layer
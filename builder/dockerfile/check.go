@@ -0,0 +1,153 @@
+package dockerfile
+
+// Dry runs of the builder. A dry run dispatches the instructions of the
+// Dockerfile like a build does, but dispatchers stop before anything with a
+// side effect: base images are not pulled, no container is created and no
+// image is committed. Remote sources are not downloaded. On top of the errors
+// returned by the dispatchers, a dry run warns about patterns that are likely
+// to cause problems, such as instructions that defeat the build cache.
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/builder/dockerfile/command"
+	"github.com/docker/docker/builder/dockerfile/parser"
+	"github.com/docker/docker/builder/dockerignore"
+	"github.com/docker/docker/pkg/fileutils"
+	"github.com/docker/docker/pkg/urlutil"
+)
+
+// check runs the Dockerfile in dry run mode. Unlike build, it doesn't stop at
+// the first error so that all the problems are reported at once. It returns
+// an error if any of the instructions would fail the build.
+func (b *Builder) check() error {
+	b.disableCommit = true
+
+	var errs int
+	for i, n := range b.dockerfile.Children {
+		select {
+		case <-b.clientCtx.Done():
+			return fmt.Errorf("Build cancelled")
+		default:
+		}
+		if err := b.dispatch(i, n); err != nil {
+			fmt.Fprintf(b.Stdout, " ---> [Error] %s\n", err)
+			errs++
+		}
+		b.lint(n, b.dockerfile.Children[i+1:])
+	}
+
+	leftoverArgs := []string{}
+	for arg := range b.options.BuildArgs {
+		if !b.isBuildArgAllowed(arg) {
+			leftoverArgs = append(leftoverArgs, arg)
+		}
+	}
+	if len(leftoverArgs) > 0 {
+		fmt.Fprintf(b.Stdout, " ---> [Error] One or more build-args %v were not consumed\n", leftoverArgs)
+		errs++
+	}
+
+	fmt.Fprintf(b.Stdout, "Dry run found %d error(s) and %d warning(s)\n", errs, b.warnings)
+	if errs > 0 {
+		return fmt.Errorf("The Dockerfile has %d error(s), the build would fail", errs)
+	}
+	return nil
+}
+
+// warn reports a problem found by a dry run that doesn't fail the build.
+func (b *Builder) warn(format string, args ...interface{}) {
+	b.warnings++
+	fmt.Fprintf(b.Stdout, " ---> [Warning] %s\n", fmt.Sprintf(format, args...))
+}
+
+// warnUnsetVariables warns about the variables that word references without
+// a default value, and that are neither set by ENV nor given a value by ARG.
+func (b *Builder) warnUnsetVariables(word string, envs []string) {
+	// Errors are reported when the word is processed by dispatch.
+	unset, _ := unsetVariables(word, envs)
+	for _, name := range unset {
+		if b.isBuildArgAllowed(name) {
+			b.warn("Variable %s is declared by ARG but has no value, it is replaced by an empty string", name)
+		} else {
+			b.warn("Variable %s is not set by ENV or ARG, it is replaced by an empty string", name)
+		}
+	}
+}
+
+// lint warns about the instructions that are valid but that are likely to
+// invalidate the build cache more often than needed. next holds the
+// instructions that follow ast in the Dockerfile.
+func (b *Builder) lint(ast *parser.Node, next []*parser.Node) {
+	var args []string
+	for n := ast.Next; n != nil; n = n.Next {
+		args = append(args, n.Value)
+	}
+
+	switch ast.Value {
+	case command.Add, command.Copy:
+		if len(args) < 2 {
+			return
+		}
+		for _, src := range args[:len(args)-1] {
+			if urlutil.IsURL(src) {
+				if !hasFlag(ast, "checksum") {
+					b.warn("%s of %s without --checksum is downloaded on every build and can change without notice", strings.ToUpper(ast.Value), src)
+				}
+				continue
+			}
+			if isWholeContext(src) && containsInstruction(next, command.Run) {
+				b.warn("%s of the whole build context invalidates the cache of the RUN instructions that follow whenever any file changes, copy only the files they need first", strings.ToUpper(ast.Value))
+			}
+		}
+	case command.Run:
+		cmdLine := strings.Join(args, " ")
+		if strings.Contains(cmdLine, "apt-get update") && !strings.Contains(cmdLine, "apt-get install") {
+			b.warn("RUN apt-get update on its own is cached, so the packages installed by later instructions can be outdated; combine it with apt-get install in the same RUN")
+		}
+	}
+}
+
+// isDockerignored reports whether path is excluded from the build context by
+// the .dockerignore file of the context.
+func (b *Builder) isDockerignored(path string) bool {
+	f, err := b.context.Open(".dockerignore")
+	if err != nil {
+		return false
+	}
+	excludes, err := dockerignore.ReadAll(f)
+	if err != nil {
+		return false
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(path)), "/")
+	ignored, _ := fileutils.Matches(path, excludes)
+	return ignored
+}
+
+func hasFlag(ast *parser.Node, name string) bool {
+	for _, flag := range ast.Flags {
+		if flag == "--"+name || strings.HasPrefix(flag, "--"+name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+func isWholeContext(src string) bool {
+	switch src {
+	case ".", "./", "/", "*":
+		return true
+	}
+	return false
+}
+
+func containsInstruction(nodes []*parser.Node, cmd string) bool {
+	for _, n := range nodes {
+		if n.Value == cmd {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package dockerfile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/builder/dockerfile/parser"
+	"golang.org/x/net/context"
+)
+
+func lintDockerfile(t *testing.T, dockerfile string) string {
+	ast, err := parser.Parse(strings.NewReader(dockerfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewBuilder(context.Background(), nil, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := &bytes.Buffer{}
+	b.Stdout = out
+	for i, n := range ast.Children {
+		b.lint(n, ast.Children[i+1:])
+	}
+	return out.String()
+}
+
+func TestLint(t *testing.T) {
+	warnings := map[string]string{
+		"FROM busybox\nCOPY . /app\nRUN make":                     "COPY of the whole build context",
+		"FROM busybox\nADD ./ /app\nRUN make":                     "ADD of the whole build context",
+		"FROM busybox\nADD http://example.com/file.tar.gz /":      "without --checksum",
+		"FROM busybox\nRUN apt-get update\nRUN apt-get install x": "RUN apt-get update on its own",
+	}
+	for dockerfile, expected := range warnings {
+		if out := lintDockerfile(t, dockerfile); !strings.Contains(out, expected) {
+			t.Fatalf("Expected a warning containing %q for:\n%s\ngot: %q", expected, dockerfile, out)
+		}
+	}
+
+	valids := []string{
+		"FROM busybox\nCOPY go.mod /app/\nRUN make\nCOPY . /app",
+		"FROM busybox\nCOPY . /app\nCMD [\"app\"]",
+		"FROM busybox\nADD --checksum=sha256:24454f830cdb571e2c4ad15481119c43b3cafd48dd869a9b2945d1036d1dc68d http://example.com/file.tar.gz /",
+		"FROM busybox\nRUN apt-get update && apt-get install -y x",
+	}
+	for _, dockerfile := range valids {
+		if out := lintDockerfile(t, dockerfile); out != "" {
+			t.Fatalf("Expected no warning for:\n%s\ngot: %q", dockerfile, out)
+		}
+	}
+}
@@ -14,9 +14,11 @@ import (
 	"regexp"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/builder"
 	"github.com/docker/docker/pkg/signal"
@@ -149,11 +151,26 @@ func add(b *Builder, args []string, attributes map[string]bool, original string)
 		return errAtLeastOneArgument("ADD")
 	}
 
+	flChown := b.flags.AddString("chown", "")
+	flChmod := b.flags.AddString("chmod", "")
+	flChecksum := b.flags.AddString("checksum", "")
 	if err := b.flags.Parse(); err != nil {
 		return err
 	}
 
-	return b.runContextCommand(args, true, true, "ADD")
+	copyOptions, err := parseCopyOptions("ADD", flChown, flChmod)
+	if err != nil {
+		return err
+	}
+
+	var checksum digest.Digest
+	if flChecksum.IsUsed() {
+		if checksum, err = digest.ParseDigest(flChecksum.Value); err != nil {
+			return fmt.Errorf("Invalid ADD --checksum value %q: %v", flChecksum.Value, err)
+		}
+	}
+
+	return b.runContextCommand(args, true, true, "ADD", copyOptions, checksum)
 }
 
 // COPY foo /path
@@ -165,11 +182,18 @@ func dispatchCopy(b *Builder, args []string, attributes map[string]bool, origina
 		return errAtLeastOneArgument("COPY")
 	}
 
+	flChown := b.flags.AddString("chown", "")
+	flChmod := b.flags.AddString("chmod", "")
 	if err := b.flags.Parse(); err != nil {
 		return err
 	}
 
-	return b.runContextCommand(args, false, false, "COPY")
+	copyOptions, err := parseCopyOptions("COPY", flChown, flChmod)
+	if err != nil {
+		return err
+	}
+
+	return b.runContextCommand(args, false, false, "COPY", copyOptions, "")
 }
 
 // FROM imagename
@@ -199,6 +223,14 @@ func from(b *Builder, args []string, attributes map[string]bool, original string
 		}
 		b.image = ""
 		b.noBaseImage = true
+	} else if b.options.DryRun {
+		// A dry run never pulls, so only a local image can be used to
+		// check the instructions against the configuration of the image.
+		image, _ = b.docker.GetImageOnBuild(name)
+		if image == nil {
+			b.warn("Image %s is not available locally, the instructions are checked without its configuration", name)
+			b.image = name
+		}
 	} else {
 		// TODO: don't use `name`, instead resolve it to a digest
 		if !b.options.PullParent {
@@ -296,6 +328,10 @@ func run(b *Builder, args []string, attributes map[string]bool, original string)
 
 	args = handleJSONArgs(args, attributes)
 
+	if b.options.DryRun {
+		return nil
+	}
+
 	if !attributes["json"] {
 		if runtime.GOOS != "windows" {
 			args = append([]string{"/bin/sh", "-c"}, args...)
@@ -626,6 +662,37 @@ func arg(b *Builder, args []string, attributes map[string]bool, original string)
 	return b.commit("", b.runConfig.Cmd, fmt.Sprintf("ARG %s", arg))
 }
 
+// parseCopyOptions validates the --chown and --chmod flags of a COPY or ADD
+// instruction.
+func parseCopyOptions(command string, flChown, flChmod *Flag) (builder.CopyOptions, error) {
+	var options builder.CopyOptions
+	if !flChown.IsUsed() && !flChmod.IsUsed() {
+		return options, nil
+	}
+	if runtime.GOOS == "windows" {
+		return options, fmt.Errorf("%s --chown and --chmod are not supported on Windows", command)
+	}
+
+	if flChown.IsUsed() {
+		parts := strings.Split(flChown.Value, ":")
+		if len(parts) > 2 || parts[0] == "" || (len(parts) == 2 && parts[1] == "") {
+			return options, fmt.Errorf("Invalid %s --chown value %q, must be in the form user[:group]", command, flChown.Value)
+		}
+		options.Chown = flChown.Value
+	}
+
+	if flChmod.IsUsed() {
+		mode, err := strconv.ParseUint(flChmod.Value, 8, 32)
+		if err != nil || mode > 07777 {
+			return options, fmt.Errorf("Invalid %s --chmod value %q, must be an octal file mode", command, flChmod.Value)
+		}
+		m := uint32(mode)
+		options.Chmod = &m
+	}
+
+	return options, nil
+}
+
 func errAtLeastOneArgument(command string) error {
 	return fmt.Errorf("%s requires at least one argument", command)
 }
@@ -0,0 +1,71 @@
+package dockerfile
+
+import (
+	"runtime"
+	"testing"
+)
+
+func parseCopyFlags(args ...string) (chown string, chmod *uint32, err error) {
+	bf := NewBFlags()
+	flChown := bf.AddString("chown", "")
+	flChmod := bf.AddString("chmod", "")
+	bf.Args = args
+	if err := bf.Parse(); err != nil {
+		return "", nil, err
+	}
+	options, err := parseCopyOptions("COPY", flChown, flChmod)
+	return options.Chown, options.Chmod, err
+}
+
+func TestParseCopyOptions(t *testing.T) {
+	chown, chmod, err := parseCopyFlags()
+	if err != nil {
+		t.Fatalf("Expected no flags to be valid, got error: %v", err)
+	}
+	if chown != "" || chmod != nil {
+		t.Fatalf("Expected no owner and mode, got %q and %v", chown, chmod)
+	}
+
+	if runtime.GOOS == "windows" {
+		if _, _, err := parseCopyFlags("--chown=1000"); err == nil {
+			t.Fatal("Expected --chown to be rejected on Windows")
+		}
+		return
+	}
+
+	for _, value := range []string{"user", "user:group", "1000", "1000:1000", "user:1000"} {
+		chown, _, err := parseCopyFlags("--chown=" + value)
+		if err != nil {
+			t.Fatalf("Expected --chown=%s to be valid, got error: %v", value, err)
+		}
+		if chown != value {
+			t.Fatalf("Expected owner %q, got %q", value, chown)
+		}
+	}
+	for _, value := range []string{"", ":", ":group", "user:", "user:group:other"} {
+		if _, _, err := parseCopyFlags("--chown=" + value); err == nil {
+			t.Fatalf("Expected --chown=%s to be invalid", value)
+		}
+	}
+
+	validModes := map[string]uint32{
+		"755":  0755,
+		"0644": 0644,
+		"4755": 04755,
+		"0":    0,
+	}
+	for value, expected := range validModes {
+		_, chmod, err := parseCopyFlags("--chmod=" + value)
+		if err != nil {
+			t.Fatalf("Expected --chmod=%s to be valid, got error: %v", value, err)
+		}
+		if chmod == nil || *chmod != expected {
+			t.Fatalf("Expected mode %o for --chmod=%s, got %v", expected, value, chmod)
+		}
+	}
+	for _, value := range []string{"", "rwx", "999", "17777", "-1"} {
+		if _, _, err := parseCopyFlags("--chmod=" + value); err == nil {
+			t.Fatalf("Expected --chmod=%s to be invalid", value)
+		}
+	}
+}
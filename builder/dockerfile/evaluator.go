@@ -166,6 +166,10 @@ func (b *Builder) dispatch(stepN int, ast *parser.Node) error {
 			var err error
 			var words []string
 
+			if b.options.DryRun {
+				b.warnUnsetVariables(str, envs)
+			}
+
 			if allowWordExpansion[cmd] {
 				words, err = ProcessWords(str, envs)
 				if err != nil {
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/builder"
 	"github.com/docker/docker/builder/dockerfile/parser"
@@ -102,6 +103,7 @@ func (b *Builder) commit(id string, autoCmd strslice.StrSlice, comment string) e
 	}
 
 	b.image = imageID
+	b.createdImages = append(b.createdImages, imageID)
 	return nil
 }
 
@@ -110,7 +112,7 @@ type copyInfo struct {
 	decompress bool
 }
 
-func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalDecompression bool, cmdName string) error {
+func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalDecompression bool, cmdName string, copyOptions builder.CopyOptions, checksum digest.Digest) error {
 	if b.context == nil {
 		return fmt.Errorf("No context given. Impossible to use %s", cmdName)
 	}
@@ -137,7 +139,13 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 			if !allowRemote {
 				return fmt.Errorf("Source can't be a URL for %s", cmdName)
 			}
-			fi, err = b.download(orig)
+			if b.options.DryRun {
+				// A dry run doesn't download remote sources, they are
+				// only counted to validate the destination.
+				infos = append(infos, copyInfo{FileInfo: builder.PathFileInfo{FileName: orig}})
+				continue
+			}
+			fi, err = b.download(orig, checksum)
 			if err != nil {
 				return err
 			}
@@ -147,8 +155,14 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 			continue
 		}
 		// not a URL
+		if checksum != "" {
+			return fmt.Errorf("%s --checksum can only be used with URL sources", cmdName)
+		}
 		subInfos, err := b.calcCopyInfo(cmdName, orig, allowLocalDecompression, true)
 		if err != nil {
+			if b.options.DryRun && b.isDockerignored(orig) {
+				return fmt.Errorf("%v (excluded by .dockerignore)", err)
+			}
 			return err
 		}
 
@@ -162,6 +176,10 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 		return fmt.Errorf("When using %s with more than one source file, the destination must be a directory and end with a /", cmdName)
 	}
 
+	if b.options.DryRun {
+		return nil
+	}
+
 	// For backwards compat, if there's just one info then use it as the
 	// cache look-up string, otherwise hash 'em all into one
 	var srcHash string
@@ -189,11 +207,21 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 		origPaths = strings.Join(origs, " ")
 	}
 
+	// The owner and permissions of the copied files are part of the
+	// cache key, so include them in the description of the command.
+	cmdDesc := cmdName
+	if copyOptions.Chown != "" {
+		cmdDesc += " --chown=" + copyOptions.Chown
+	}
+	if copyOptions.Chmod != nil {
+		cmdDesc += fmt.Sprintf(" --chmod=%04o", *copyOptions.Chmod)
+	}
+
 	cmd := b.runConfig.Cmd
 	if runtime.GOOS != "windows" {
-		b.runConfig.Cmd = strslice.StrSlice{"/bin/sh", "-c", fmt.Sprintf("#(nop) %s %s in %s", cmdName, srcHash, dest)}
+		b.runConfig.Cmd = strslice.StrSlice{"/bin/sh", "-c", fmt.Sprintf("#(nop) %s %s in %s", cmdDesc, srcHash, dest)}
 	} else {
-		b.runConfig.Cmd = strslice.StrSlice{"cmd", "/S", "/C", fmt.Sprintf("REM (nop) %s %s in %s", cmdName, srcHash, dest)}
+		b.runConfig.Cmd = strslice.StrSlice{"cmd", "/S", "/C", fmt.Sprintf("REM (nop) %s %s in %s", cmdDesc, srcHash, dest)}
 	}
 	defer func(cmd strslice.StrSlice) { b.runConfig.Cmd = cmd }(cmd)
 
@@ -209,7 +237,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 	}
 	b.tmpContainers[container.ID] = struct{}{}
 
-	comment := fmt.Sprintf("%s %s in %s", cmdName, origPaths, dest)
+	comment := fmt.Sprintf("%s %s in %s", cmdDesc, origPaths, dest)
 
 	// Twiddle the destination when its a relative path - meaning, make it
 	// relative to the WORKINGDIR
@@ -224,7 +252,7 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 	}
 
 	for _, info := range infos {
-		if err := b.docker.CopyOnBuild(container.ID, dest, info.FileInfo, info.decompress); err != nil {
+		if err := b.docker.CopyOnBuild(container.ID, dest, info.FileInfo, info.decompress, copyOptions); err != nil {
 			return err
 		}
 	}
@@ -232,7 +260,11 @@ func (b *Builder) runContextCommand(args []string, allowRemote bool, allowLocalD
 	return b.commit(container.ID, cmd, comment)
 }
 
-func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
+// download fetches srcURL into a temporary directory. Credentials from the
+// build's auth configuration are sent if one matches the host of the URL. If
+// checksum is set, the content must match it and is used as the cache key
+// instead of the content and modification time of the download.
+func (b *Builder) download(srcURL string, checksum digest.Digest) (fi builder.FileInfo, err error) {
 	// get filename from URL
 	u, err := url.Parse(srcURL)
 	if err != nil {
@@ -250,10 +282,22 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 	}
 
 	// Initiate the download
-	resp, err := httputils.Download(srcURL)
+	req, err := http.NewRequest("GET", srcURL, nil)
+	if err != nil {
+		return
+	}
+	if authConfig, ok := authConfigForURL(b.options.AuthConfigs, u); ok {
+		if authConfig.RegistryToken != "" {
+			req.Header.Set("Authorization", "Bearer "+authConfig.RegistryToken)
+		} else {
+			req.SetBasicAuth(authConfig.Username, authConfig.Password)
+		}
+	}
+	resp, err := httputils.DownloadRequest(req)
 	if err != nil {
 		return
 	}
+	defer resp.Body.Close()
 
 	// Prepare file in a tmp dir
 	tmpDir, err := ioutils.TempDir("", "docker-remote")
@@ -271,11 +315,18 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 		return
 	}
 
+	var dst io.Writer = tmpFile
+	var digester digest.Digester
+	if checksum != "" {
+		digester = checksum.Algorithm().New()
+		dst = io.MultiWriter(tmpFile, digester.Hash())
+	}
+
 	stdoutFormatter := b.Stdout.(*streamformatter.StdoutFormatter)
 	progressOutput := stdoutFormatter.StreamFormatter.NewProgressOutput(stdoutFormatter.Writer, true)
 	progressReader := progress.NewProgressReader(resp.Body, progressOutput, resp.ContentLength, "", "Downloading")
 	// Download and dump result to tmp file
-	if _, err = io.Copy(tmpFile, progressReader); err != nil {
+	if _, err = io.Copy(dst, progressReader); err != nil {
 		tmpFile.Close()
 		return
 	}
@@ -287,6 +338,13 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 	}
 	tmpFile.Close()
 
+	if checksum != "" {
+		if actual := digester.Digest(); actual != checksum {
+			err = fmt.Errorf("Checksum mismatch for %s: expected %s, got %s", srcURL, checksum, actual)
+			return
+		}
+	}
+
 	// Set the mtime to the Last-Modified header value if present
 	// Otherwise just remove atime and mtime
 	mTime := time.Time{}
@@ -304,6 +362,13 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 		return
 	}
 
+	fileInfo := builder.PathFileInfo{FileInfo: tmpFileSt, FilePath: tmpFileName}
+	if checksum != "" {
+		// The filename is part of the key because it determines the
+		// destination path when copying into a directory.
+		return &builder.HashedFileInfo{FileInfo: fileInfo, FileHash: checksum.String() + ":" + filename}, nil
+	}
+
 	// Calc the checksum, even if we're using the cache
 	r, err := archive.Tar(tmpFileName, archive.Uncompressed)
 	if err != nil {
@@ -318,7 +383,26 @@ func (b *Builder) download(srcURL string) (fi builder.FileInfo, err error) {
 	}
 	hash := tarSum.Sum(nil)
 	r.Close()
-	return &builder.HashedFileInfo{FileInfo: builder.PathFileInfo{FileInfo: tmpFileSt, FilePath: tmpFileName}, FileHash: hash}, nil
+	return &builder.HashedFileInfo{FileInfo: fileInfo, FileHash: hash}, nil
+}
+
+// authConfigForURL returns the entry of authConfigs matching the host of u.
+// Entries without a scheme, like those of registries, only match https URLs
+// so that credentials are not sent in the clear.
+func authConfigForURL(authConfigs map[string]types.AuthConfig, u *url.URL) (types.AuthConfig, bool) {
+	for key, authConfig := range authConfigs {
+		scheme, host := "https", key
+		if i := strings.Index(key, "://"); i != -1 {
+			scheme, host = key[:i], key[i+3:]
+		}
+		if i := strings.Index(host, "/"); i != -1 {
+			host = host[:i]
+		}
+		if strings.EqualFold(scheme, u.Scheme) && strings.EqualFold(host, u.Host) {
+			return authConfig, true
+		}
+	}
+	return types.AuthConfig{}, false
 }
 
 func (b *Builder) calcCopyInfo(cmdName, origPath string, allowLocalDecompression, allowWildcards bool) ([]copyInfo, error) {
@@ -538,9 +622,11 @@ func (b *Builder) create() (string, error) {
 
 	// TODO: why not embed a hostconfig in builder?
 	hostConfig := &container.HostConfig{
-		Isolation: b.options.Isolation,
-		ShmSize:   b.options.ShmSize,
-		Resources: resources,
+		Isolation:   b.options.Isolation,
+		ShmSize:     b.options.ShmSize,
+		Resources:   resources,
+		NetworkMode: container.NetworkMode(b.options.NetworkMode),
+		ExtraHosts:  b.options.ExtraHosts,
 	}
 
 	config := *b.runConfig
@@ -593,7 +679,7 @@ func (b *Builder) run(cID string) (err error) {
 		}
 	}()
 
-	if err := b.docker.ContainerStart(cID, nil); err != nil {
+	if err := b.docker.ContainerStart(cID, nil, ""); err != nil {
 		return err
 	}
 
@@ -0,0 +1,43 @@
+package dockerfile
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestAuthConfigForURL(t *testing.T) {
+	authConfigs := map[string]types.AuthConfig{
+		"https://index.docker.io/v1/": {Username: "hub"},
+		"artifacts.example.com":       {Username: "artifacts"},
+		"http://plain.example.com":    {Username: "plain"},
+		"registry.example.com:5000":   {Username: "registry"},
+	}
+
+	cases := map[string]string{
+		"https://index.docker.io/file.tar":           "hub",
+		"https://artifacts.example.com/a/file.tar":   "artifacts",
+		"https://ARTIFACTS.example.com/file.tar":     "artifacts",
+		"http://plain.example.com/file.tar":          "plain",
+		"https://registry.example.com:5000/file.tar": "registry",
+		// Entries without a scheme only match https.
+		"http://artifacts.example.com/file.tar": "",
+		"https://plain.example.com/file.tar":    "",
+		"https://registry.example.com/file.tar": "",
+		"https://other.example.com/file.tar":    "",
+	}
+	for rawurl, expected := range cases {
+		u, err := url.Parse(rawurl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		authConfig, ok := authConfigForURL(authConfigs, u)
+		if ok != (expected != "") {
+			t.Fatalf("Expected a match for %s to be %v, got %v", rawurl, expected != "", ok)
+		}
+		if authConfig.Username != expected {
+			t.Fatalf("Expected credentials of %q for %s, got %q", expected, rawurl, authConfig.Username)
+		}
+	}
+}
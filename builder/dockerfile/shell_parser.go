@@ -18,6 +18,7 @@ type shellWord struct {
 	scanner scanner.Scanner
 	envs    []string
 	pos     int
+	unset   []string // names referenced without a default that are not in envs
 }
 
 // ProcessWord will use the 'env' list of environment variables,
@@ -51,6 +52,20 @@ func ProcessWords(word string, env []string) ([]string, error) {
 	return words, err
 }
 
+// unsetVariables returns the names of the variables that 'word' references
+// without a default value, like $xxx or ${xxx}, and that are not set in the
+// 'env' list of environment variables.
+func unsetVariables(word string, env []string) ([]string, error) {
+	sw := &shellWord{
+		word: word,
+		envs: env,
+		pos:  0,
+	}
+	sw.scanner.Init(strings.NewReader(word))
+	_, _, err := sw.process()
+	return sw.unset, err
+}
+
 func (sw *shellWord) process() (string, []string, error) {
 	return sw.processStopOn(scanner.EOF)
 }
@@ -228,7 +243,7 @@ func (sw *shellWord) processDollar() (string, error) {
 		if ch == '}' {
 			// Normal ${xx} case
 			sw.scanner.Next()
-			return sw.getEnv(name), nil
+			return sw.getEnvOrRecordUnset(name), nil
 		}
 		if ch == ':' {
 			// Special ${xx:...} format processing
@@ -270,7 +285,7 @@ func (sw *shellWord) processDollar() (string, error) {
 	if name == "" {
 		return "$", nil
 	}
-	return sw.getEnv(name), nil
+	return sw.getEnvOrRecordUnset(name), nil
 }
 
 func (sw *shellWord) processName() string {
@@ -295,20 +310,33 @@ func (sw *shellWord) processName() string {
 }
 
 func (sw *shellWord) getEnv(name string) string {
+	value, _ := sw.lookupEnv(name)
+	return value
+}
+
+func (sw *shellWord) getEnvOrRecordUnset(name string) string {
+	value, ok := sw.lookupEnv(name)
+	if !ok && name != "" {
+		sw.unset = append(sw.unset, name)
+	}
+	return value
+}
+
+func (sw *shellWord) lookupEnv(name string) (string, bool) {
 	for _, env := range sw.envs {
 		i := strings.Index(env, "=")
 		if i < 0 {
 			if name == env {
 				// Should probably never get here, but just in case treat
 				// it like "var" and "var=" are the same
-				return ""
+				return "", true
 			}
 			continue
 		}
 		if name != env[:i] {
 			continue
 		}
-		return env[i+1:]
+		return env[i+1:], true
 	}
-	return ""
+	return "", false
 }
@@ -141,3 +141,26 @@ func TestGetEnv(t *testing.T) {
 		t.Fatalf("8 - 'car' should map to 'hat'")
 	}
 }
+
+func TestUnsetVariables(t *testing.T) {
+	env := []string{"SET=value", "EMPTY="}
+	cases := map[string][]string{
+		"no variables":                 nil,
+		"$SET ${SET} $EMPTY":           nil,
+		"$UNSET":                       {"UNSET"},
+		"${UNSET}/bin":                 {"UNSET"},
+		"${UNSET:-default}":            nil,
+		"${UNSET:+alternative}":        nil,
+		"$SET $ONE ${TWO} '$QUOTED'":   {"ONE", "TWO"},
+		"\"$DOUBLE\" \\$ESCAPED $ ${}": {"DOUBLE"},
+	}
+	for word, expected := range cases {
+		unset, err := unsetVariables(word, env)
+		if err != nil {
+			t.Fatalf("Error processing %q: %v", word, err)
+		}
+		if strings.Join(unset, ",") != strings.Join(expected, ",") {
+			t.Fatalf("Expected %q to reference the unset variables %v, got %v", word, expected, unset)
+		}
+	}
+}
@@ -28,6 +28,7 @@ type Command struct {
 var dockerCommands = []Command{
 	{"attach", "Attach to a running container"},
 	{"build", "Build an image from a Dockerfile"},
+	{"checkpoint", "Manage container checkpoints"},
 	{"commit", "Create a new image from a container's changes"},
 	{"cp", "Copy files/folders between a container and the local filesystem"},
 	{"create", "Create a new container"},
@@ -36,6 +37,7 @@ var dockerCommands = []Command{
 	{"exec", "Run a command in a running container"},
 	{"export", "Export a container's filesystem as a tar archive"},
 	{"history", "Show the history of an image"},
+	{"image", "Manage images"},
 	{"images", "List images"},
 	{"import", "Import the contents from a tarball to create a filesystem image"},
 	{"info", "Display system-wide information"},
@@ -45,6 +47,7 @@ var dockerCommands = []Command{
 	{"login", "Log in to a Docker registry"},
 	{"logout", "Log out from a Docker registry"},
 	{"logs", "Fetch the logs of a container"},
+	{"manifest", "Manage manifest lists"},
 	{"network", "Manage Docker networks"},
 	{"pause", "Pause all processes within a container"},
 	{"port", "List port mappings or a specific mapping for the CONTAINER"},
@@ -31,6 +31,7 @@ import (
 	runconfigopts "github.com/docker/docker/runconfig/opts"
 	"github.com/docker/docker/volume"
 	containertypes "github.com/docker/engine-api/types/container"
+	mounttypes "github.com/docker/engine-api/types/mount"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/docker/go-connections/nat"
 	"github.com/docker/libnetwork"
@@ -40,7 +41,13 @@ import (
 	"github.com/opencontainers/runc/libcontainer/label"
 )
 
-const configFileName = "config.v2.json"
+const (
+	configFileName = "config.v2.json"
+
+	// DefaultStopTimeout is the timeout (in seconds) used to stop a
+	// container which doesn't set its own.
+	DefaultStopTimeout = 10
+)
 
 var (
 	errInvalidEndpoint = fmt.Errorf("invalid endpoint while building port map info")
@@ -290,6 +297,18 @@ func (container *Container) ConfigPath() (string, error) {
 	return container.GetRootResourcePath(configFileName)
 }
 
+// CheckpointDir returns the directory the checkpoints of the container are
+// stored in.
+func (container *Container) CheckpointDir() string {
+	return filepath.Join(container.Root, "checkpoints")
+}
+
+// SeccompAuditPath returns the path of the file the system calls learned for
+// the container in seccomp audit mode are stored in.
+func (container *Container) SeccompAuditPath() string {
+	return filepath.Join(container.Root, "seccomp-audit.json")
+}
+
 // StartLogger starts a new logger driver for the container.
 func (container *Container) StartLogger(cfg containertypes.LogConfig) (logger.Logger, error) {
 	c, err := logger.GetLogDriver(cfg.Type)
@@ -519,7 +538,7 @@ func copyEscapable(dst io.Writer, src io.ReadCloser, keys []byte) (written int64
 // ShouldRestart decides whether the daemon should restart the container or not.
 // This is based on the container's restart policy.
 func (container *Container) ShouldRestart() bool {
-	shouldRestart, _, _ := container.restartManager.ShouldRestart(uint32(container.ExitCode), container.HasBeenManuallyStopped)
+	shouldRestart, _, _ := container.restartManager.ShouldRestart(uint32(container.ExitCode), container.HasBeenManuallyStopped, container.FinishedAt.Sub(container.StartedAt))
 	return shouldRestart
 }
 
@@ -532,6 +551,7 @@ func (container *Container) AddMountPointWithVolume(destination string, vol volu
 		RW:          rw,
 		Volume:      vol,
 		CopyData:    volume.DefaultCopyMode,
+		Type:        mounttypes.TypeVolume,
 	}
 }
 
@@ -553,6 +573,14 @@ func (container *Container) StopSignal() int {
 	return int(stopSignal)
 }
 
+// StopTimeout returns the timeout (in seconds) used to stop the container.
+func (container *Container) StopTimeout() int {
+	if container.Config.StopTimeout != nil {
+		return *container.Config.StopTimeout
+	}
+	return DefaultStopTimeout
+}
+
 // InitDNSHostConfig ensures that the dns fields are never nil.
 // New containers don't ever have those fields nil,
 // but pre created containers can still have those nil values.
@@ -864,6 +892,44 @@ func (container *Container) BuildCreateEndpointOptions(n libnetwork.Network, epC
 	return createOptions, nil
 }
 
+// updateRestartPolicy updates the restart policy of the container. A policy
+// with a name replaces the current one, so its options can be set back to
+// zero. Otherwise, it's merged into the current one and the options left to
+// zero keep their current value.
+func (container *Container) updateRestartPolicy(policy containertypes.RestartPolicy) {
+	rp := &container.HostConfig.RestartPolicy
+	if policy.Name != "" {
+		*rp = policy
+		return
+	}
+	if policy.MaxDelay != 0 {
+		rp.MaxDelay = policy.MaxDelay
+	}
+	if policy.ResetAfter != 0 {
+		rp.ResetAfter = policy.ResetAfter
+	}
+	if policy.Window != 0 {
+		rp.Window = policy.Window
+	}
+	if policy.MaxRestartsInWindow != 0 {
+		rp.MaxRestartsInWindow = policy.MaxRestartsInWindow
+	}
+}
+
+// RestartBackoff returns the current backoff state of the restart manager
+// of the container, or nil if the container has none.
+func (container *Container) RestartBackoff() *restartmanager.State {
+	type stateGetter interface {
+		State() restartmanager.State
+	}
+
+	if rm, ok := container.restartManager.(stateGetter); ok {
+		state := rm.State()
+		return &state
+	}
+	return nil
+}
+
 // UpdateMonitor updates monitor configure for running container
 func (container *Container) UpdateMonitor(restartPolicy containertypes.RestartPolicy) {
 	type policySetter interface {
@@ -2,6 +2,7 @@ package container
 
 import (
 	"testing"
+	"time"
 
 	"github.com/docker/docker/pkg/signal"
 	"github.com/docker/engine-api/types/container"
@@ -34,3 +35,27 @@ func TestContainerStopSignal(t *testing.T) {
 		t.Fatalf("Expected 9, got %v", s)
 	}
 }
+
+func TestContainerUpdateRestartPolicy(t *testing.T) {
+	c := &Container{
+		CommonContainer: CommonContainer{
+			HostConfig: &container.HostConfig{
+				RestartPolicy: container.RestartPolicy{Name: "always", MaxDelay: time.Minute, Window: time.Hour, MaxRestartsInWindow: 5},
+			},
+		},
+	}
+
+	// without a name, the options which are set are merged
+	c.updateRestartPolicy(container.RestartPolicy{ResetAfter: time.Second})
+	expected := container.RestartPolicy{Name: "always", MaxDelay: time.Minute, ResetAfter: time.Second, Window: time.Hour, MaxRestartsInWindow: 5}
+	if c.HostConfig.RestartPolicy != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, c.HostConfig.RestartPolicy)
+	}
+
+	// with a name, the policy is replaced and options can be unset
+	expected = container.RestartPolicy{Name: "on-failure", MaximumRetryCount: 3, ResetAfter: time.Second}
+	c.updateRestartPolicy(expected)
+	if c.HostConfig.RestartPolicy != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, c.HostConfig.RestartPolicy)
+	}
+}
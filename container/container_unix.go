@@ -17,6 +17,7 @@ import (
 	"github.com/docker/docker/utils"
 	"github.com/docker/docker/volume"
 	containertypes "github.com/docker/engine-api/types/container"
+	mounttypes "github.com/docker/engine-api/types/mount"
 	"github.com/opencontainers/runc/libcontainer/label"
 )
 
@@ -283,9 +284,7 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 	}
 
 	// update HostConfig of container
-	if hostConfig.RestartPolicy.Name != "" {
-		container.HostConfig.RestartPolicy = hostConfig.RestartPolicy
-	}
+	container.updateRestartPolicy(hostConfig.RestartPolicy)
 
 	if err := container.ToDisk(); err != nil {
 		logrus.Errorf("Error saving updated container: %v", err)
@@ -381,7 +380,7 @@ func copyOwnership(source, destination string) error {
 }
 
 // TmpfsMounts returns the list of tmpfs mounts
-func (container *Container) TmpfsMounts() []Mount {
+func (container *Container) TmpfsMounts() ([]Mount, error) {
 	var mounts []Mount
 	for dest, data := range container.HostConfig.Tmpfs {
 		mounts = append(mounts, Mount{
@@ -390,7 +389,21 @@ func (container *Container) TmpfsMounts() []Mount {
 			Data:        data,
 		})
 	}
-	return mounts
+	for dest, mnt := range container.MountPoints {
+		if mnt.Type != mounttypes.TypeTmpfs {
+			continue
+		}
+		data, err := volume.ConvertTmpfsOptions(mnt.Spec.TmpfsOptions, mnt.Spec.ReadOnly)
+		if err != nil {
+			return nil, err
+		}
+		mounts = append(mounts, Mount{
+			Source:      "tmpfs",
+			Destination: dest,
+			Data:        data,
+		})
+	}
+	return mounts, nil
 }
 
 // cleanResourcePath cleans a resource path and prepares to combine with mnt path
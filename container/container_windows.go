@@ -50,8 +50,8 @@ func (container *Container) UnmountVolumes(forceSyscall bool, volumeEventLog fun
 }
 
 // TmpfsMounts returns the list of tmpfs mounts
-func (container *Container) TmpfsMounts() []Mount {
-	return nil
+func (container *Container) TmpfsMounts() ([]Mount, error) {
+	return nil, nil
 }
 
 // UpdateContainer updates configuration of a container
@@ -67,9 +67,7 @@ func (container *Container) UpdateContainer(hostConfig *containertypes.HostConfi
 		return fmt.Errorf("Resource updating isn't supported on Windows")
 	}
 	// update HostConfig of container
-	if hostConfig.RestartPolicy.Name != "" {
-		container.HostConfig.RestartPolicy = hostConfig.RestartPolicy
-	}
+	container.updateRestartPolicy(hostConfig.RestartPolicy)
 	return nil
 }
 
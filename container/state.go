@@ -5,6 +5,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/docker/engine-api/types"
 	"github.com/docker/go-units"
 )
 
@@ -26,6 +27,7 @@ type State struct {
 	Error             string // contains last known error when starting the container
 	StartedAt         time.Time
 	FinishedAt        time.Time
+	Usage             *types.ContainerUsage // resources used by the last run, set when it exits
 	waitChan          chan struct{}
 }
 
@@ -184,6 +186,7 @@ func (s *State) SetRunning(pid int, initial bool) {
 	s.Paused = false
 	s.Restarting = false
 	s.ExitCode = 0
+	s.Usage = nil
 	s.Pid = pid
 	if initial {
 		s.StartedAt = time.Now().UTC()
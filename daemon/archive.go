@@ -335,11 +335,12 @@ func (daemon *Daemon) containerCopy(container *container.Container, resource str
 // specified by a container object.
 // TODO: make sure callers don't unnecessarily convert destPath with filepath.FromSlash (Copy does it already).
 // CopyOnBuild should take in abstract paths (with slashes) and the implementation should convert it to OS-specific paths.
-func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileInfo, decompress bool) error {
+func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileInfo, decompress bool, options builder.CopyOptions) error {
 	srcPath := src.Path()
 	destExists := true
 	destDir := false
 	rootUID, rootGID := daemon.GetRemappedUIDGID()
+	uid, gid := rootUID, rootGID
 
 	// Work in daemon-local OS specific file paths
 	destPath = filepath.FromSlash(destPath)
@@ -354,6 +355,13 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 	}
 	defer daemon.Unmount(c)
 
+	if options.Chown != "" {
+		uid, gid, err = daemon.getChownIDs(c, options.Chown)
+		if err != nil {
+			return err
+		}
+	}
+
 	dest, err := c.GetResourcePath(destPath)
 	if err != nil {
 		return err
@@ -389,7 +397,7 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 		if err := archiver.CopyWithTar(srcPath, destPath); err != nil {
 			return err
 		}
-		return fixPermissions(srcPath, destPath, rootUID, rootGID, destExists)
+		return fixPermissions(srcPath, destPath, uid, gid, options.Chmod, destExists)
 	}
 	if decompress && archive.IsArchivePath(srcPath) {
 		// Only try to untar if it is a file and that we've been told to decompress (when ADD-ing a remote file)
@@ -403,14 +411,26 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 			tarDest = filepath.Dir(destPath)
 		}
 
+		// The entries keep the ownership recorded in the archive unless
+		// another owner was requested.
+		tarOptions := &archive.TarOptions{
+			UIDMaps: uidMaps,
+			GIDMaps: gidMaps,
+		}
+		if options.Chown != "" {
+			tarOptions.ChownOpts = &archive.TarChownOptions{UID: uid, GID: gid}
+		}
+		if options.Chmod != nil {
+			tarOptions.ChmodOpts = &archive.TarChmodOptions{Mode: int64(*options.Chmod)}
+		}
+
 		// try to successfully untar the orig
-		err := archiver.UntarPath(srcPath, tarDest)
-		/*
-			if err != nil {
-				logrus.Errorf("Couldn't untar to %s: %v", tarDest, err)
-			}
-		*/
-		return err
+		srcArchive, err := os.Open(srcPath)
+		if err != nil {
+			return err
+		}
+		defer srcArchive.Close()
+		return chrootarchive.Untar(srcArchive, tarDest, tarOptions)
 	}
 
 	// only needed for fixPermissions, but might as well put it before CopyFileWithTar
@@ -425,5 +445,5 @@ func (daemon *Daemon) CopyOnBuild(cID string, destPath string, src builder.FileI
 		return err
 	}
 
-	return fixPermissions(srcPath, destPath, rootUID, rootGID, destExists)
+	return fixPermissions(srcPath, destPath, uid, gid, options.Chmod, destExists)
 }
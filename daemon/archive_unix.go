@@ -3,9 +3,15 @@
 package daemon
 
 import (
-	"github.com/docker/docker/container"
+	"io"
 	"os"
 	"path/filepath"
+	"syscall"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/idtools"
+	"github.com/docker/docker/pkg/symlink"
+	"github.com/opencontainers/runc/libcontainer/user"
 )
 
 // checkIfPathIsInAVolume checks if the path is in a volume. If it is, it
@@ -24,7 +30,7 @@ func checkIfPathIsInAVolume(container *container.Container, absPath string) (boo
 	return toVolume, nil
 }
 
-func fixPermissions(source, destination string, uid, gid int, destExisted bool) error {
+func fixPermissions(source, destination string, uid, gid int, mode *uint32, destExisted bool) error {
 	// If the destination didn't already exist, or the destination isn't a
 	// directory, then we should Lchown the destination. Otherwise, we shouldn't
 	// Lchown the destination.
@@ -52,6 +58,65 @@ func fixPermissions(source, destination string, uid, gid int, destExisted bool)
 		}
 
 		fullpath = filepath.Join(destination, cleaned)
-		return os.Lchown(fullpath, uid, gid)
+		if err := os.Lchown(fullpath, uid, gid); err != nil {
+			return err
+		}
+		// There is no lchmod, so symlinks keep their permissions.
+		if mode != nil && info.Mode()&os.ModeSymlink == 0 {
+			return syscall.Chmod(fullpath, *mode)
+		}
+		return nil
 	})
 }
+
+// getChownIDs resolves a "user[:group]" specification against the
+// /etc/passwd and /etc/group files of the container, and returns the
+// matching uid and gid on the host.
+func (daemon *Daemon) getChownIDs(c *container.Container, chown string) (int, int, error) {
+	passwdPath, err := user.GetPasswdPath()
+	if err != nil {
+		return 0, 0, err
+	}
+	groupPath, err := user.GetGroupPath()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// Missing files are not an error as long as the user and group are
+	// numeric, so only pass the files that could be opened.
+	var passwd, group io.Reader
+	if f, err := openContainerFile(c, passwdPath); err == nil {
+		defer f.Close()
+		passwd = f
+	}
+	if f, err := openContainerFile(c, groupPath); err == nil {
+		defer f.Close()
+		group = f
+	}
+
+	execUser, err := user.GetExecUser(chown, nil, passwd, group)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	uidMaps, gidMaps := daemon.GetUIDGIDMaps()
+	uid, err := idtools.ToHost(execUser.Uid, uidMaps)
+	if err != nil {
+		return 0, 0, err
+	}
+	gid, err := idtools.ToHost(execUser.Gid, gidMaps)
+	if err != nil {
+		return 0, 0, err
+	}
+	return uid, gid, nil
+}
+
+// openContainerFile opens the file at path p in the root filesystem of the
+// container, which must be mounted.
+func openContainerFile(c *container.Container, p string) (*os.File, error) {
+	fp, err := symlink.FollowSymlinkInScope(filepath.Join(c.BaseFS, p), c.BaseFS)
+	if err != nil {
+		return nil, err
+	}
+	return os.Open(fp)
+}
@@ -1,6 +1,10 @@
 package daemon
 
-import "github.com/docker/docker/container"
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+)
 
 // checkIfPathIsInAVolume checks if the path is in a volume. If it is, it
 // cannot be in a read-only volume. If it  is not in a volume, the container
@@ -12,7 +16,11 @@ func checkIfPathIsInAVolume(container *container.Container, absPath string) (boo
 	return false, nil
 }
 
-func fixPermissions(source, destination string, uid, gid int, destExisted bool) error {
+func fixPermissions(source, destination string, uid, gid int, mode *uint32, destExisted bool) error {
 	// chown is not supported on Windows
 	return nil
 }
+
+func (daemon *Daemon) getChownIDs(c *container.Container, chown string) (int, int, error) {
+	return 0, 0, fmt.Errorf("chown is not supported on Windows")
+}
@@ -0,0 +1,129 @@
+package daemon
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/engine-api/types"
+)
+
+// validateCheckpointName checks that a checkpoint name designates a
+// directory inside the checkpoint directory of a container.
+func validateCheckpointName(name string) error {
+	if name == "" {
+		return fmt.Errorf("Checkpoint name must not be empty")
+	}
+	if strings.Contains(name, "/") || strings.Contains(name, "..") {
+		return fmt.Errorf("Invalid checkpoint name '%s', it must not contain '/' or '..'", name)
+	}
+	return nil
+}
+
+// checkpointExists returns an error if the container has no checkpoint
+// with the given name.
+func checkpointExists(c *container.Container, name string) error {
+	if err := validateCheckpointName(name); err != nil {
+		return err
+	}
+	if fi, err := os.Stat(filepath.Join(c.CheckpointDir(), name)); err != nil || !fi.IsDir() {
+		return fmt.Errorf("No such checkpoint: %s", name)
+	}
+	return nil
+}
+
+// CheckpointCreate checkpoints the process running in a container with CRIU
+func (daemon *Daemon) CheckpointCreate(name string, config types.CheckpointCreateOptions) error {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
+
+	if !container.IsRunning() {
+		return errNotRunning{container.ID}
+	}
+
+	if err := validateCheckpointName(config.CheckpointID); err != nil {
+		return err
+	}
+
+	checkpointDir := container.CheckpointDir()
+	if err := os.MkdirAll(checkpointDir, 0700); err != nil {
+		return fmt.Errorf("Cannot create checkpoint directory for container %s: %v", container.ID, err)
+	}
+
+	var manuallyStopped bool
+	if config.Exit {
+		// The container exits once it's been checkpointed, this has to be
+		// set before so that the exit is recorded as a manual stop.
+		// libcontainerd cancels the restart policy once the checkpoint
+		// has been created.
+		container.Lock()
+		manuallyStopped = container.HasBeenManuallyStopped
+		container.HasBeenManuallyStopped = true
+		container.Unlock()
+	}
+
+	if err := daemon.containerd.CreateCheckpoint(container.ID, config.CheckpointID, checkpointDir, config.Exit); err != nil {
+		if config.Exit {
+			container.Lock()
+			container.HasBeenManuallyStopped = manuallyStopped
+			container.Unlock()
+		}
+		return fmt.Errorf("Cannot checkpoint container %s: %s", container.ID, err)
+	}
+
+	// containerd versions that don't support the checkpoint directory
+	// store the checkpoint in their own state directory instead.
+	if err := checkpointExists(container, config.CheckpointID); err != nil {
+		return fmt.Errorf("Cannot checkpoint container %s: containerd didn't store the checkpoint in %s", container.ID, checkpointDir)
+	}
+
+	daemon.LogContainerEvent(container, "checkpoint")
+
+	return nil
+}
+
+// CheckpointDelete deletes the specified checkpoint
+func (daemon *Daemon) CheckpointDelete(name string, checkpoint string) error {
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return err
+	}
+
+	if err := checkpointExists(container, checkpoint); err != nil {
+		return err
+	}
+
+	return daemon.containerd.DeleteCheckpoint(container.ID, checkpoint, container.CheckpointDir())
+}
+
+// CheckpointList lists all checkpoints of the specified container
+func (daemon *Daemon) CheckpointList(name string) ([]types.Checkpoint, error) {
+	var out []types.Checkpoint
+
+	container, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each checkpoint is stored by CRIU in its own directory.
+	dirs, err := ioutil.ReadDir(container.CheckpointDir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return out, nil
+		}
+		return nil, err
+	}
+
+	for _, d := range dirs {
+		if d.IsDir() {
+			out = append(out, types.Checkpoint{Name: d.Name()})
+		}
+	}
+
+	return out, nil
+}
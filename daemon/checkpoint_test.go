@@ -0,0 +1,16 @@
+package daemon
+
+import "testing"
+
+func TestValidateCheckpointName(t *testing.T) {
+	for _, name := range []string{"checkpoint1", "a.b", "before-upgrade"} {
+		if err := validateCheckpointName(name); err != nil {
+			t.Fatalf("expected %q to be valid: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "..", "../..", "foo/bar", "/foo", "a..b"} {
+		if err := validateCheckpointName(name); err == nil {
+			t.Fatalf("expected %q to be invalid", name)
+		}
+	}
+}
@@ -94,6 +94,9 @@ func merge(userConf, imageConf *containertypes.Config) error {
 	if userConf.StopSignal == "" {
 		userConf.StopSignal = imageConf.StopSignal
 	}
+	if userConf.StopTimeout == nil {
+		userConf.StopTimeout = imageConf.StopTimeout
+	}
 	return nil
 }
 
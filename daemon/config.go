@@ -8,12 +8,14 @@ import (
 	"io/ioutil"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/discovery"
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/registry"
+	"github.com/docker/go-units"
 	"github.com/imdario/mergo"
 )
 
@@ -27,8 +29,10 @@ const (
 // Use this to differentiate these options
 // with others like the ones in CommonTLSOptions.
 var flatOptions = map[string]bool{
-	"cluster-store-opts": true,
-	"log-opts":           true,
+	"cluster-store-opts":   true,
+	"log-opts":             true,
+	"registry-mirrors-for": true,
+	"runtimes":             true,
 }
 
 // LogConfig represents the default log configuration.
@@ -71,12 +75,18 @@ type CommonConfig struct {
 	GraphDriver          string              `json:"storage-driver,omitempty"`
 	GraphOptions         []string            `json:"storage-opts,omitempty"`
 	Labels               []string            `json:"labels,omitempty"`
+	LiveRestore          bool                `json:"live-restore,omitempty"`
+	MaxDownloadBandwidth string              `json:"max-download-bandwidth,omitempty"`
+	MaxUploadBandwidth   string              `json:"max-upload-bandwidth,omitempty"`
 	Mtu                  int                 `json:"mtu,omitempty"`
+	PartialDownloadTTL   string              `json:"partial-download-ttl,omitempty"`
 	Pidfile              string              `json:"pidfile,omitempty"`
 	RawLogs              bool                `json:"raw-logs,omitempty"`
 	Root                 string              `json:"graph,omitempty"`
 	SocketGroup          string              `json:"group,omitempty"`
 	TrustKeyPath         string              `json:"-"`
+	TrustPolicy          string              `json:"trust-policy,omitempty"`
+	VerifyLayers         bool                `json:"verify-layers,omitempty"`
 
 	// ClusterStore is the storage backend used for the cluster information. It is used by both
 	// multihost networking (to store networks and endpoints information) and by the node discovery
@@ -135,6 +145,37 @@ func (config *Config) InstallCommonFlags(cmd *flag.FlagSet, usageFn func(string)
 	cmd.StringVar(&config.ClusterAdvertise, []string{"-cluster-advertise"}, "", usageFn("Address or interface name to advertise"))
 	cmd.StringVar(&config.ClusterStore, []string{"-cluster-store"}, "", usageFn("Set the cluster store"))
 	cmd.Var(opts.NewNamedMapOpts("cluster-store-opts", config.ClusterOpts, nil), []string{"-cluster-store-opt"}, usageFn("Set cluster store options"))
+	cmd.StringVar(&config.MaxDownloadBandwidth, []string{"-max-download-bandwidth"}, "", usageFn("Limit the total bandwidth of image pulls per second"))
+	cmd.StringVar(&config.MaxUploadBandwidth, []string{"-max-upload-bandwidth"}, "", usageFn("Limit the total bandwidth of image pushes per second"))
+	cmd.StringVar(&config.PartialDownloadTTL, []string{"-partial-download-ttl"}, "", usageFn("How long to keep partially downloaded layers for a later pull to resume (default 24h)"))
+	cmd.StringVar(&config.TrustPolicy, []string{"-trust-policy"}, "", usageFn("Path to the content trust policy file"))
+	cmd.BoolVar(&config.VerifyLayers, []string{"-verify-layers"}, false, usageFn("Verify the integrity of image layers on startup"))
+}
+
+// parseBandwidth parses a bandwidth limit in bytes per second, such as 10MB.
+// An empty value means there is no limit.
+func parseBandwidth(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+	bandwidth, err := units.FromHumanSize(value)
+	if err != nil || bandwidth < 0 {
+		return 0, fmt.Errorf("invalid bandwidth %q, must be a size per second such as 10MB", value)
+	}
+	return bandwidth, nil
+}
+
+// parsePartialDownloadTTL parses how long partially downloaded layers are
+// kept, such as 12h. An empty value means defaultPartialDownloadTTL.
+func parsePartialDownloadTTL(value string) (time.Duration, error) {
+	if value == "" {
+		return defaultPartialDownloadTTL, nil
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return 0, fmt.Errorf("invalid partial download TTL %q, must be a duration such as 12h", value)
+	}
+	return ttl, nil
 }
 
 // IsValueSet returns true if a configuration value
@@ -375,5 +416,22 @@ func validateConfiguration(config *Config) error {
 		}
 	}
 
+	// validate the mirrors of each registry
+	if err := registry.ValidateRegistryMirrors(config.RegistryMirrors); err != nil {
+		return err
+	}
+
+	// validate the bandwidth limits
+	for _, bandwidth := range []string{config.MaxDownloadBandwidth, config.MaxUploadBandwidth} {
+		if _, err := parseBandwidth(bandwidth); err != nil {
+			return err
+		}
+	}
+
+	// validate the partial download TTL
+	if _, err := parsePartialDownloadTTL(config.PartialDownloadTTL); err != nil {
+		return err
+	}
+
 	return nil
 }
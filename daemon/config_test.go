@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/docker/opts"
 	"github.com/docker/docker/pkg/mflag"
@@ -276,3 +277,70 @@ func TestValidateConfiguration(t *testing.T) {
 		t.Fatal("expected error, got nil")
 	}
 }
+
+func TestParseBandwidth(t *testing.T) {
+	valids := map[string]int64{
+		"":     0,
+		"0":    0,
+		"512":  512,
+		"10kB": 10000,
+		"10MB": 10000000,
+		"1GB":  1000000000,
+	}
+	for value, expected := range valids {
+		bandwidth, err := parseBandwidth(value)
+		if err != nil {
+			t.Fatalf("expected %q to be valid, got error %v", value, err)
+		}
+		if bandwidth != expected {
+			t.Fatalf("expected %q to be %d bytes per second, got %d", value, expected, bandwidth)
+		}
+	}
+
+	for _, value := range []string{"fast", "-1MB", "10MB/s"} {
+		if _, err := parseBandwidth(value); err == nil {
+			t.Fatalf("expected %q to be invalid", value)
+		}
+	}
+
+	c := &Config{
+		CommonConfig: CommonConfig{
+			MaxDownloadBandwidth: "fast",
+		},
+	}
+	if err := validateConfiguration(c); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
+
+func TestParsePartialDownloadTTL(t *testing.T) {
+	valids := map[string]time.Duration{
+		"":    defaultPartialDownloadTTL,
+		"90m": 90 * time.Minute,
+		"72h": 72 * time.Hour,
+	}
+	for value, expected := range valids {
+		ttl, err := parsePartialDownloadTTL(value)
+		if err != nil {
+			t.Fatalf("expected %q to be valid, got error %v", value, err)
+		}
+		if ttl != expected {
+			t.Fatalf("expected %q to be %s, got %s", value, expected, ttl)
+		}
+	}
+
+	for _, value := range []string{"forever", "0", "-1h", "24"} {
+		if _, err := parsePartialDownloadTTL(value); err == nil {
+			t.Fatalf("expected %q to be invalid", value)
+		}
+	}
+
+	c := &Config{
+		CommonConfig: CommonConfig{
+			PartialDownloadTTL: "forever",
+		},
+	}
+	if err := validateConfiguration(c); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/opts"
 	flag "github.com/docker/docker/pkg/mflag"
 	runconfigopts "github.com/docker/docker/runconfig/opts"
+	"github.com/docker/engine-api/types"
 	"github.com/docker/go-units"
 )
 
@@ -31,7 +32,10 @@ type Config struct {
 	EnableCors           bool                     `json:"api-enable-cors,omitempty"`
 	EnableSelinuxSupport bool                     `json:"selinux-enabled,omitempty"`
 	ExecRoot             string                   `json:"exec-root,omitempty"`
+	Init                 bool                     `json:"init,omitempty"`
+	InitPath             string                   `json:"init-path,omitempty"`
 	RemappedRoot         string                   `json:"userns-remap,omitempty"`
+	Runtimes             map[string]types.Runtime `json:"runtimes,omitempty"`
 	Ulimits              map[string]*units.Ulimit `json:"default-ulimits,omitempty"`
 }
 
@@ -86,6 +90,34 @@ func (config *Config) InstallFlags(cmd *flag.FlagSet, usageFn func(string) strin
 	cmd.StringVar(&config.CgroupParent, []string{"-cgroup-parent"}, "", usageFn("Set parent cgroup for all containers"))
 	cmd.StringVar(&config.RemappedRoot, []string{"-userns-remap"}, "", usageFn("User/Group setting for user namespaces"))
 	cmd.StringVar(&config.ContainerdAddr, []string{"-containerd"}, "", usageFn("Path to containerd socket"))
+	cmd.BoolVar(&config.LiveRestore, []string{"-live-restore"}, false, usageFn("Enable live restore of docker when containers are still running"))
+	config.Runtimes = make(map[string]types.Runtime)
+	cmd.Var(runconfigopts.NewNamedRuntimeOpt("runtimes", &config.Runtimes, stockRuntimeName), []string{"-add-runtime"}, usageFn("Register an additional OCI compatible runtime"))
+
+	cmd.BoolVar(&config.Init, []string{"-init"}, false, usageFn("Run an init in the container to forward signals and reap processes"))
+	cmd.StringVar(&config.InitPath, []string{"-init-path"}, "", usageFn("Path to the docker-init binary"))
 
 	config.attachExperimentalFlags(cmd, usageFn)
 }
+
+// GetRuntime returns the runtime registered under the given name, or the
+// stock runtime if the name is empty. It returns nil if there is no such
+// runtime.
+func (config *Config) GetRuntime(name string) *types.Runtime {
+	if name == "" {
+		name = stockRuntimeName
+	}
+	if rt, ok := config.Runtimes[name]; ok {
+		return &rt
+	}
+	return nil
+}
+
+// GetInitPath returns the configured path of the init binary, or the
+// default one if none was set.
+func (config *Config) GetInitPath() string {
+	if config.InitPath != "" {
+		return config.InitPath
+	}
+	return DefaultInitBinary
+}
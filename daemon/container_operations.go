@@ -168,7 +168,8 @@ func (daemon *Daemon) buildSandboxOptions(container *container.Container, n libn
 
 	// Link feature is supported only for the default bridge network.
 	// return if this call to build join options is not for default bridge network
-	if n.Name() != defaultNetName {
+	// or if it is building the options of a sandbox restored on daemon start.
+	if n == nil || n.Name() != defaultNetName {
 		return sboxOptions, nil
 	}
 
@@ -678,6 +679,9 @@ func (daemon *Daemon) getNetworkedContainer(containerID, connectedContainerID st
 }
 
 func (daemon *Daemon) releaseNetwork(container *container.Container) {
+	if daemon.netController == nil {
+		return
+	}
 	if container.HostConfig.NetworkMode.IsContainer() || container.Config.NetworkDisabled {
 		return
 	}
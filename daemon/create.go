@@ -14,6 +14,7 @@ import (
 	containertypes "github.com/docker/engine-api/types/container"
 	networktypes "github.com/docker/engine-api/types/network"
 	"github.com/opencontainers/runc/libcontainer/label"
+	"golang.org/x/net/context"
 )
 
 // ContainerCreate creates a container.
@@ -63,6 +64,9 @@ func (daemon *Daemon) create(params types.ContainerCreateConfig) (retC *containe
 			return nil, err
 		}
 		imgID = img.ID()
+		if err := daemon.verifyImageTrust(context.Background(), imgID, "create"); err != nil {
+			return nil, err
+		}
 	}
 
 	if err := daemon.mergeAndVerifyConfig(params.Config, img); err != nil {
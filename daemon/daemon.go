@@ -36,7 +36,9 @@ import (
 	_ "github.com/docker/docker/daemon/graphdriver/register"
 	"github.com/docker/docker/daemon/logger"
 	"github.com/docker/docker/daemon/network"
+	"github.com/docker/docker/daemon/trust"
 	dmetadata "github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/staging"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/dockerversion"
 	"github.com/docker/docker/image"
@@ -61,6 +63,7 @@ import (
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/runconfig"
 	"github.com/docker/docker/utils"
+	"github.com/docker/docker/volume"
 	volumedrivers "github.com/docker/docker/volume/drivers"
 	"github.com/docker/docker/volume/local"
 	"github.com/docker/docker/volume/store"
@@ -78,6 +81,11 @@ const (
 	// maxUploadConcurrency is the maximum number of uploads that
 	// may take place at a time for each push.
 	maxUploadConcurrency = 5
+	// defaultPartialDownloadTTL is how long a partially downloaded layer is
+	// kept for a later pull to resume it, unless --partial-download-ttl is
+	// set. A day lets a pull interrupted overnight be resumed, without
+	// keeping abandoned downloads forever.
+	defaultPartialDownloadTTL = 24 * time.Hour
 )
 
 var (
@@ -104,12 +112,16 @@ type Daemon struct {
 	execCommands              *exec.Store
 	referenceStore            reference.Store
 	downloadManager           *xfer.LayerDownloadManager
+	stagingStore              *staging.Store
+	trustPolicy               *trust.Policy
 	uploadManager             *xfer.LayerUploadManager
 	distributionMetadataStore dmetadata.Store
 	trustKey                  libtrust.PrivateKey
 	idIndex                   *truncindex.TruncIndex
 	configStore               *Config
 	statsCollector            *statsCollector
+	usageMonitor              *usageMonitor
+	seccompAuditor            *seccompAuditor
 	defaultLogConfig          containertypes.LogConfig
 	RegistryService           *registry.Service
 	EventsService             *events.Events
@@ -126,6 +138,7 @@ type Daemon struct {
 	nameIndex                 *registrar.Registrar
 	linkIndex                 *linkIndex
 	containerd                libcontainerd.Client
+	containerdRemote          libcontainerd.Remote
 	defaultIsolation          containertypes.Isolation // Default isolation mode on Windows
 }
 
@@ -285,6 +298,7 @@ func (daemon *Daemon) restore() error {
 	}
 	var wg sync.WaitGroup
 	var mapLock sync.Mutex
+	activeSandboxes := make(map[string]interface{})
 	for _, c := range containers {
 		wg.Add(1)
 		go func(c *container.Container) {
@@ -296,10 +310,22 @@ func (daemon *Daemon) restore() error {
 					logrus.Errorf("Failed to ReinitRWLayer for %s due to %s", c.ID, err)
 					return
 				}
+				daemon.seccompAuditor.restore(c)
 				if err := daemon.containerd.Restore(c.ID, libcontainerd.WithRestartManager(rm)); err != nil {
 					logrus.Errorf("Failed to restore with containerd: %q", err)
 					return
 				}
+				// The container is still running when it was live
+				// restored, keep its network sandbox.
+				if c.IsRunning() && !c.HostConfig.NetworkMode.IsContainer() && c.NetworkSettings.SandboxID != "" {
+					options, err := daemon.buildSandboxOptions(c, nil)
+					if err != nil {
+						logrus.Warnf("Failed to build sandbox option to restore container %s: %v", c.ID, err)
+					}
+					mapLock.Lock()
+					activeSandboxes[c.NetworkSettings.SandboxID] = options
+					mapLock.Unlock()
+				}
 			}
 			// fixme: only if not running
 			// get list of containers we need to restart
@@ -317,6 +343,14 @@ func (daemon *Daemon) restore() error {
 	}
 	wg.Wait()
 
+	// The network controller is initialized once containerd has told
+	// which containers are still running, so that the network sandboxes
+	// of live restored containers are kept instead of being cleaned up.
+	daemon.netController, err = daemon.initNetworkController(daemon.configStore, activeSandboxes)
+	if err != nil {
+		return fmt.Errorf("Error initializing network controller: %v", err)
+	}
+
 	// migrate any legacy links from sqlite
 	linkdbFile := filepath.Join(daemon.root, "linkgraph.db")
 	var legacyLinkDB *graphdb.Database
@@ -361,7 +395,7 @@ func (daemon *Daemon) restore() error {
 					}
 				}
 			}
-			if err := daemon.containerStart(c); err != nil {
+			if err := daemon.containerStart(c, ""); err != nil {
 				logrus.Errorf("Failed to start container %s: %s", c.ID, err)
 			}
 			close(chNotify)
@@ -706,6 +740,17 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 	d.downloadManager = xfer.NewLayerDownloadManager(d.layerStore, maxDownloadConcurrency)
 	d.uploadManager = xfer.NewLayerUploadManager(maxUploadConcurrency)
 
+	maxDownloadBandwidth, err := parseBandwidth(config.MaxDownloadBandwidth)
+	if err != nil {
+		return nil, err
+	}
+	d.downloadManager.SetBandwidthLimit(maxDownloadBandwidth)
+	maxUploadBandwidth, err := parseBandwidth(config.MaxUploadBandwidth)
+	if err != nil {
+		return nil, err
+	}
+	d.uploadManager.SetBandwidthLimit(maxUploadBandwidth)
+
 	ifs, err := image.NewFSStoreBackend(filepath.Join(imageRoot, "imagedb"))
 	if err != nil {
 		return nil, err
@@ -733,11 +778,27 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 		return nil, err
 	}
 
+	if config.TrustPolicy != "" {
+		d.trustPolicy, err = trust.LoadPolicy(config.TrustPolicy, trust.NewNotaryResolver(filepath.Join(trustDir, "notary"), registryService))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	distributionMetadataStore, err := dmetadata.NewFSMetadataStore(filepath.Join(imageRoot, "distribution"))
 	if err != nil {
 		return nil, err
 	}
 
+	partialDownloadTTL, err := parsePartialDownloadTTL(config.PartialDownloadTTL)
+	if err != nil {
+		return nil, err
+	}
+	d.stagingStore, err = staging.NewStore(filepath.Join(config.Root, "downloads"), partialDownloadTTL)
+	if err != nil {
+		return nil, err
+	}
+
 	eventsService := events.New()
 
 	referenceStore, err := reference.NewReferenceStore(filepath.Join(imageRoot, "repositories.json"))
@@ -761,11 +822,6 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 		return nil, err
 	}
 
-	d.netController, err = d.initNetworkController(config)
-	if err != nil {
-		return nil, fmt.Errorf("Error initializing network controller: %v", err)
-	}
-
 	sysInfo := sysinfo.New(false)
 	// Check if Devices cgroup is mounted, it is hard requirement for container security,
 	// on Linux/FreeBSD.
@@ -782,6 +838,8 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 	d.trustKey = trustKey
 	d.idIndex = truncindex.NewTruncIndex([]string{})
 	d.statsCollector = d.newStatsCollector(1 * time.Second)
+	d.usageMonitor = newUsageMonitor()
+	d.seccompAuditor = newSeccompAuditor(d)
 	d.defaultLogConfig = containertypes.LogConfig{
 		Type:   config.LogConfig.Type,
 		Config: config.LogConfig.Config,
@@ -799,6 +857,11 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 
 	go d.execCommandGC()
 
+	if config.VerifyLayers {
+		d.verifyLayersOnStartup()
+	}
+
+	d.containerdRemote = containerdRemote
 	d.containerd, err = containerdRemote.Client(d)
 	if err != nil {
 		return nil, err
@@ -812,6 +875,7 @@ func NewDaemon(config *Config, registryService *registry.Service, containerdRemo
 }
 
 func (daemon *Daemon) shutdownContainer(c *container.Container) error {
+	stopTimeout := c.StopTimeout()
 	// TODO(windows): Handle docker restart with paused containers
 	if c.IsPaused() {
 		// To terminate a process in freezer cgroup, we should send
@@ -828,8 +892,8 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 		if err := daemon.containerUnpause(c); err != nil {
 			return fmt.Errorf("Failed to unpause container %s with error: %v", c.ID, err)
 		}
-		if _, err := c.WaitStop(10 * time.Second); err != nil {
-			logrus.Debugf("container %s failed to exit in 10 second of SIGTERM, sending SIGKILL to force", c.ID)
+		if _, err := c.WaitStop(time.Duration(stopTimeout) * time.Second); err != nil {
+			logrus.Debugf("container %s failed to exit in %d second of SIGTERM, sending SIGKILL to force", c.ID, stopTimeout)
 			sig, ok := signal.SignalMap["KILL"]
 			if !ok {
 				return fmt.Errorf("System does not support SIGKILL")
@@ -841,8 +905,8 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 			return err
 		}
 	}
-	// If container failed to exit in 10 seconds of SIGTERM, then using the force
-	if err := daemon.containerStop(c, 10); err != nil {
+	// If container failed to exit in stopTimeout seconds of SIGTERM, then using the force
+	if err := daemon.containerStop(c, stopTimeout); err != nil {
 		return fmt.Errorf("Stop container %s with error: %v", c.ID, err)
 	}
 
@@ -850,9 +914,38 @@ func (daemon *Daemon) shutdownContainer(c *container.Container) error {
 	return nil
 }
 
+// ShutdownTimeout returns the timeout (in seconds) before the daemon
+// forcibly shuts down: the longest stop timeout of the running
+// containers, plus a few seconds to clean up.
+func (daemon *Daemon) ShutdownTimeout() int {
+	const graceTimeout = 5
+
+	shutdownTimeout := container.DefaultStopTimeout
+	if daemon.containers != nil {
+		for _, c := range daemon.containers.List() {
+			if !c.IsRunning() {
+				continue
+			}
+			if stopTimeout := c.StopTimeout(); stopTimeout > shutdownTimeout {
+				shutdownTimeout = stopTimeout
+			}
+		}
+	}
+	return shutdownTimeout + graceTimeout
+}
+
 // Shutdown stops the daemon.
 func (daemon *Daemon) Shutdown() error {
 	daemon.shutdown = true
+	// Keep mounts and networking running on daemon shutdown if
+	// we are to keep containers running and restore them.
+	if daemon.configStore != nil && daemon.configStore.LiveRestore && daemon.containers != nil {
+		// check if there are any running containers, if none we should do some cleanup
+		if ls, err := daemon.Containers(&types.ContainerListOptions{}); len(ls) != 0 || err != nil {
+			return nil
+		}
+	}
+
 	if daemon.containers != nil {
 		logrus.Debug("starting clean shutdown of all containers...")
 		daemon.containers.ApplyAll(func(c *container.Container) {
@@ -970,11 +1063,12 @@ func isBrokenPipe(e error) bool {
 // ExportImage exports a list of images to the given output stream. The
 // exported images are archived into a tar when written to the output
 // stream. All images with the given tag and all versions containing
-// the same tag are exported. names is the set of tags to export, and
+// the same tag are exported. names is the set of tags to export, the
+// layers of the images or chain IDs in excludes are left out, and
 // outStream is the writer which the images are written to.
-func (daemon *Daemon) ExportImage(names []string, outStream io.Writer) error {
+func (daemon *Daemon) ExportImage(names, excludes []string, format string, outStream io.Writer) error {
 	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore)
-	return imageExporter.Save(names, outStream)
+	return imageExporter.Save(names, excludes, format, outStream)
 }
 
 // LookupImage looks up an image by name and returns it as an ImageInspect
@@ -1036,6 +1130,7 @@ func (daemon *Daemon) LookupImage(name string) (*types.ImageInspect, error) {
 		Config:          img.Config,
 		Architecture:    img.Architecture,
 		Os:              img.OS,
+		Variant:         img.Variant,
 		Size:            size,
 		VirtualSize:     size, // TODO: field unused, deprecate
 		RootFS:          rootFSToAPIType(img.RootFS),
@@ -1050,9 +1145,14 @@ func (daemon *Daemon) LookupImage(name string) (*types.ImageInspect, error) {
 
 // LoadImage uploads a set of images into the repository. This is the
 // complement of ImageExport.  The input stream is an uncompressed tar
-// ball containing images and metadata.
+// ball containing images and metadata. Images are only tagged in the
+// repositories the trust policy covers if they are signed.
 func (daemon *Daemon) LoadImage(inTar io.ReadCloser, outStream io.Writer, quiet bool) error {
-	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, daemon.referenceStore)
+	referenceStore := daemon.referenceStore
+	if daemon.trustPolicy != nil {
+		referenceStore = &trustedReferenceStore{Store: referenceStore, daemon: daemon}
+	}
+	imageExporter := tarexport.NewTarExporter(daemon.imageStore, daemon.layerStore, referenceStore)
 	return imageExporter.Load(inTar, outStream, quiet)
 }
 
@@ -1325,6 +1425,10 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 				return nil, err
 			}
 		}
+
+		if config.StopTimeout != nil && *config.StopTimeout < 0 {
+			return nil, fmt.Errorf("Invalid stop timeout %d: it must not be negative", *config.StopTimeout)
+		}
 	}
 
 	if hostConfig == nil {
@@ -1336,6 +1440,16 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 		return nil, err
 	}
 
+	for _, cfg := range hostConfig.Mounts {
+		if err := volume.ValidateMountConfig(&cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := validateRestartPolicy(hostConfig.RestartPolicy, update); err != nil {
+		return nil, err
+	}
+
 	for port := range hostConfig.PortBindings {
 		_, portStr := nat.SplitProtoPort(string(port))
 		if _, err := nat.ParsePort(portStr); err != nil {
@@ -1353,6 +1467,22 @@ func (daemon *Daemon) verifyContainerSettings(hostConfig *containertypes.HostCon
 	return verifyPlatformContainerSettings(daemon, hostConfig, config, update)
 }
 
+// validateRestartPolicy validates the backoff options of a restart policy.
+// On update, the policy only holds the options to change.
+func validateRestartPolicy(p containertypes.RestartPolicy, update bool) error {
+	if p.MaxDelay < 0 || p.ResetAfter < 0 || p.Window < 0 || p.MaxRestartsInWindow < 0 {
+		return fmt.Errorf("Restart policy options must not be negative")
+	}
+	if !update && (p.Window > 0) != (p.MaxRestartsInWindow > 0) {
+		return fmt.Errorf("Restart window and maximum restarts in window must be set together")
+	}
+	hasBackoff := p.MaxDelay > 0 || p.ResetAfter > 0 || p.Window > 0 || p.MaxRestartsInWindow > 0
+	if !update && hasBackoff && (p.Name == "" || p.IsNone()) {
+		return fmt.Errorf("Restart policy options are not valid with restart policy of \"no\"")
+	}
+	return nil
+}
+
 // Checks if the client set configurations for more than one network while creating a container
 func (daemon *Daemon) verifyNetworkingConfig(nwConfig *networktypes.NetworkingConfig) error {
 	if nwConfig == nil || len(nwConfig.EndpointsConfig) <= 1 {
@@ -1488,6 +1618,7 @@ func (daemon *Daemon) initDiscovery(config *Config) error {
 // - Daemon labels.
 // - Daemon debug log level.
 // - Cluster discovery (reconfigure and restart).
+// - Daemon live restore
 func (daemon *Daemon) Reload(config *Config) error {
 	daemon.configStore.reloadLock.Lock()
 	defer daemon.configStore.reloadLock.Unlock()
@@ -1497,6 +1628,12 @@ func (daemon *Daemon) Reload(config *Config) error {
 	if config.IsValueSet("debug") {
 		daemon.configStore.Debug = config.Debug
 	}
+	if config.IsValueSet("live-restore") {
+		daemon.configStore.LiveRestore = config.LiveRestore
+		if err := daemon.containerdRemote.UpdateOptions(libcontainerd.WithLiveRestore(config.LiveRestore)); err != nil {
+			return err
+		}
+	}
 	return daemon.reloadClusterDiscovery(config)
 }
 
@@ -1545,7 +1682,7 @@ func (daemon *Daemon) reloadClusterDiscovery(config *Config) error {
 	if daemon.netController == nil {
 		return nil
 	}
-	netOptions, err := daemon.networkOptions(daemon.configStore)
+	netOptions, err := daemon.networkOptions(daemon.configStore, nil)
 	if err != nil {
 		logrus.Warnf("Failed to reload configuration with network controller: %v", err)
 		return nil
@@ -1569,7 +1706,7 @@ func isBridgeNetworkDisabled(config *Config) bool {
 	return config.bridgeConfig.Iface == disableNetworkBridge
 }
 
-func (daemon *Daemon) networkOptions(dconfig *Config) ([]nwconfig.Option, error) {
+func (daemon *Daemon) networkOptions(dconfig *Config, activeSandboxes map[string]interface{}) ([]nwconfig.Option, error) {
 	options := []nwconfig.Option{}
 	if dconfig == nil {
 		return options, nil
@@ -1604,6 +1741,11 @@ func (daemon *Daemon) networkOptions(dconfig *Config) ([]nwconfig.Option, error)
 
 	options = append(options, nwconfig.OptionLabels(dconfig.Labels))
 	options = append(options, driverOptions(dconfig)...)
+
+	if daemon.configStore != nil && daemon.configStore.LiveRestore && len(activeSandboxes) != 0 {
+		options = append(options, nwconfig.OptionActiveSandboxes(activeSandboxes))
+	}
+
 	return options, nil
 }
 
@@ -55,6 +55,15 @@ const (
 	// constant for cgroup drivers
 	cgroupFsDriver      = "cgroupfs"
 	cgroupSystemdDriver = "systemd"
+
+	// DefaultRuntimeBinary is the runtime containerd runs containers with
+	// when no other runtime is specified.
+	DefaultRuntimeBinary = "docker-runc"
+	// stockRuntimeName is the name the default runtime is registered under.
+	stockRuntimeName = "runc"
+	// DefaultInitBinary is the init binary bind-mounted into containers
+	// started with --init.
+	DefaultInitBinary = "docker-init"
 )
 
 func getMemoryResources(config containertypes.Resources) *specs.Memory {
@@ -563,6 +572,9 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 			return warnings, fmt.Errorf("cgroup-parent for systemd cgroup should be a valid slice named as \"xxx.slice\"")
 		}
 	}
+	if hostConfig.Runtime != "" && daemon.configStore.GetRuntime(hostConfig.Runtime) == nil {
+		return warnings, fmt.Errorf("Unknown runtime specified %s", hostConfig.Runtime)
+	}
 	return warnings, nil
 }
 
@@ -586,6 +598,15 @@ func verifyDaemonSettings(config *Config) error {
 			return fmt.Errorf("cgroup-parent for systemd cgroup should be a valid slice named as \"xxx.slice\"")
 		}
 	}
+
+	if config.Runtimes == nil {
+		config.Runtimes = make(map[string]types.Runtime)
+	}
+	if _, ok := config.Runtimes[stockRuntimeName]; ok {
+		return fmt.Errorf("runtime name '%s' is reserved", stockRuntimeName)
+	}
+	config.Runtimes[stockRuntimeName] = types.Runtime{Path: DefaultRuntimeBinary}
+
 	return nil
 }
 
@@ -632,8 +653,8 @@ func configureKernelSecuritySupport(config *Config, driverName string) error {
 	return nil
 }
 
-func (daemon *Daemon) initNetworkController(config *Config) (libnetwork.NetworkController, error) {
-	netOptions, err := daemon.networkOptions(config)
+func (daemon *Daemon) initNetworkController(config *Config, activeSandboxes map[string]interface{}) (libnetwork.NetworkController, error) {
+	netOptions, err := daemon.networkOptions(config, activeSandboxes)
 	if err != nil {
 		return nil, err
 	}
@@ -653,6 +674,13 @@ func (daemon *Daemon) initNetworkController(config *Config) (libnetwork.NetworkC
 		return nil, fmt.Errorf("Error creating default \"host\" network: %v", err)
 	}
 
+	// Containers that kept running across a live restore are still
+	// attached to the existing default bridge, so it can't be recreated.
+	if len(activeSandboxes) > 0 {
+		logrus.Info("There are old running containers, the network config will not take affect")
+		return controller, nil
+	}
+
 	if !config.DisableBridge {
 		// Initialize default driver "bridge"
 		if err := initBridgeDriver(controller, config); err != nil {
@@ -1140,3 +1168,8 @@ func rootFSToAPIType(rootfs *image.RootFS) types.RootFS {
 		Layers: layers,
 	}
 }
+
+// getRuntimes returns the OCI runtimes containers can be run with.
+func (daemon *Daemon) getRuntimes() map[string]types.Runtime {
+	return daemon.configStore.Runtimes
+}
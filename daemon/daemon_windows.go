@@ -122,6 +122,14 @@ func verifyPlatformContainerSettings(daemon *Daemon, hostConfig *containertypes.
 		return warnings, err
 	}
 
+	if hostConfig.Runtime != "" {
+		return warnings, fmt.Errorf("Runtime option is not supported on Windows")
+	}
+
+	if hostConfig.Init != nil && *hostConfig.Init {
+		return warnings, fmt.Errorf("Init option is not supported on Windows")
+	}
+
 	return warnings, nil
 }
 
@@ -154,8 +162,8 @@ func configureMaxThreads(config *Config) error {
 	return nil
 }
 
-func (daemon *Daemon) initNetworkController(config *Config) (libnetwork.NetworkController, error) {
-	netOptions, err := daemon.networkOptions(config)
+func (daemon *Daemon) initNetworkController(config *Config, activeSandboxes map[string]interface{}) (libnetwork.NetworkController, error) {
+	netOptions, err := daemon.networkOptions(config, activeSandboxes)
 	if err != nil {
 		return nil, err
 	}
@@ -474,3 +482,9 @@ func rootFSToAPIType(rootfs *image.RootFS) types.RootFS {
 		BaseLayer: rootfs.BaseLayer,
 	}
 }
+
+// getRuntimes returns nil as the runtime of containers can't be chosen
+// on Windows.
+func (daemon *Daemon) getRuntimes() map[string]types.Runtime {
+	return nil
+}
@@ -3,18 +3,24 @@ package daemon
 import (
 	"fmt"
 	"io"
+	"runtime"
 	"strings"
+	"syscall"
 	"time"
 
 	"golang.org/x/net/context"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/daemon/exec"
 	"github.com/docker/docker/errors"
 	"github.com/docker/docker/libcontainerd"
 	"github.com/docker/docker/pkg/pools"
+	"github.com/docker/docker/pkg/signal"
+	"github.com/docker/docker/pkg/system"
 	"github.com/docker/docker/pkg/term"
+	"github.com/docker/docker/utils"
 	"github.com/docker/engine-api/types"
 	"github.com/docker/engine-api/types/strslice"
 )
@@ -106,6 +112,10 @@ func (d *Daemon) ContainerExecCreate(config *types.ExecConfig) (string, error) {
 		}
 	}
 
+	if config.WorkingDir != "" && !system.IsAbs(config.WorkingDir) {
+		return "", fmt.Errorf("The working directory '%s' is invalid. It needs to be an absolute path", config.WorkingDir)
+	}
+
 	execConfig := exec.NewConfig()
 	execConfig.OpenStdin = config.AttachStdin
 	execConfig.OpenStdout = config.AttachStdout
@@ -120,6 +130,16 @@ func (d *Daemon) ContainerExecCreate(config *types.ExecConfig) (string, error) {
 	if len(execConfig.User) == 0 {
 		execConfig.User = container.Config.User
 	}
+	// Without variables of its own, the exec runs with the environment the
+	// container was started with.
+	if len(config.Env) > 0 {
+		linkedEnv, err := d.setupLinkedContainers(container)
+		if err != nil {
+			return "", err
+		}
+		execConfig.Env = utils.ReplaceOrAppendEnvValues(container.CreateDaemonEnvironment(linkedEnv), config.Env)
+	}
+	execConfig.WorkingDir = config.WorkingDir
 
 	d.registerExecCommand(container, execConfig)
 
@@ -190,6 +210,7 @@ func (d *Daemon) ContainerExecStart(name string, stdin io.ReadCloser, stdout io.
 
 	p := libcontainerd.Process{
 		Args:     append([]string{ec.Entrypoint}, ec.Args...),
+		Env:      ec.Env,
 		Terminal: ec.Tty,
 	}
 
@@ -210,6 +231,57 @@ func (d *Daemon) ContainerExecStart(name string, stdin io.ReadCloser, stdout io.
 	return nil
 }
 
+// ContainerExecKill sends a signal to a running exec process. If no signal is
+// given (sig 0), the process is sent SIGKILL.
+func (d *Daemon) ContainerExecKill(name string, sig uint64) error {
+	ec, err := d.getExecConfig(name)
+	if err != nil {
+		return err
+	}
+
+	if sig == 0 {
+		sig = uint64(syscall.SIGKILL)
+	}
+	if !signal.ValidSignalForPlatform(syscall.Signal(sig)) {
+		return fmt.Errorf("The %s daemon does not support signal %d", runtime.GOOS, sig)
+	}
+
+	ec.Lock()
+	running := ec.Running
+	ec.Unlock()
+	if !running {
+		err := fmt.Errorf("Exec %s is not running", ec.ID)
+		return errors.NewRequestConflictError(err)
+	}
+
+	if err := d.containerd.SignalProcess(ec.ContainerID, ec.ID, int(sig)); err != nil {
+		return err
+	}
+
+	c := d.containers.Get(ec.ContainerID)
+	attributes := map[string]string{
+		"execID": ec.ID,
+		"signal": fmt.Sprintf("%d", sig),
+	}
+	d.LogContainerEventWithAttributes(c, "exec_kill", attributes)
+	return nil
+}
+
+// ContainerExecList returns the execs of a container which have not exited
+// yet, whether they are running or only created.
+func (d *Daemon) ContainerExecList(name string) ([]*backend.ExecInspect, error) {
+	container, err := d.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+
+	execs := []*backend.ExecInspect{}
+	for _, e := range container.ExecCommands.Commands() {
+		execs = append(execs, execInspect(e))
+	}
+	return execs, nil
+}
+
 // execCommandGC runs a ticker to clean up the daemon references
 // of exec configs that are no longer part of the container.
 func (d *Daemon) execCommandGC() {
@@ -27,6 +27,8 @@ type Config struct {
 	Tty         bool
 	Privileged  bool
 	User        string
+	Env         []string
+	WorkingDir  string
 }
 
 // NewConfig initializes the a new exec configuration
@@ -22,5 +22,8 @@ func execSetPlatformOpt(c *container.Container, ec *exec.Config, p *libcontainer
 	if ec.Privileged {
 		p.Capabilities = caps.GetAllCapabilities()
 	}
+	if len(ec.WorkingDir) > 0 {
+		p.Cwd = &ec.WorkingDir
+	}
 	return nil
 }
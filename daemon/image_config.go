@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/builder/dockerfile"
+	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+	"github.com/docker/engine-api/types"
+	containertypes "github.com/docker/engine-api/types/container"
+)
+
+// ConfigImage creates a new image from the image name, with the same layers
+// and its config modified by the Dockerfile instructions of options.Changes,
+// as `commit --change` would without a container. The new image can
+// optionally be tagged into a repository.
+func (daemon *Daemon) ConfigImage(name string, options types.ImageConfigOptions) (string, error) {
+	var newRef reference.Named
+	if options.RepositoryName != "" {
+		var err error
+		newRef, err = reference.WithName(options.RepositoryName)
+		if err != nil {
+			return "", err
+		}
+		if options.Tag != "" {
+			if newRef, err = reference.WithTag(newRef, options.Tag); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	img, err := daemon.GetImage(name)
+	if err != nil {
+		return "", daemon.imageNotExistToErrcode(err)
+	}
+
+	config := img.Config
+	if config == nil {
+		config = &containertypes.Config{}
+	}
+	newConfig, err := dockerfile.BuildFromConfig(config, options.Changes)
+	if err != nil {
+		return "", err
+	}
+
+	h := image.History{
+		Author:     options.Author,
+		Created:    time.Now().UTC(),
+		CreatedBy:  "#(nop) " + strings.Join(options.Changes, "; "),
+		Comment:    options.Comment,
+		EmptyLayer: true,
+	}
+
+	history := make([]image.History, len(img.History), len(img.History)+1)
+	copy(history, img.History)
+	history = append(history, h)
+
+	imgConfig, err := json.Marshal(&image.Image{
+		V1Image: image.V1Image{
+			DockerVersion: dockerversion.Version,
+			Config:        newConfig,
+			Architecture:  img.Architecture,
+			OS:            img.OS,
+			Author:        options.Author,
+			Comment:       options.Comment,
+			Created:       h.Created,
+		},
+		RootFS:     img.RootFS,
+		History:    history,
+		OSFeatures: img.OSFeatures,
+		OSVersion:  img.OSVersion,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	id, err := daemon.imageStore.Create(imgConfig)
+	if err != nil {
+		return "", err
+	}
+
+	if err := daemon.imageStore.SetParent(id, img.ID()); err != nil {
+		return "", err
+	}
+
+	if newRef != nil {
+		if err := daemon.TagImageWithReference(id, newRef); err != nil {
+			return "", err
+		}
+	}
+
+	return id.String(), nil
+}
@@ -5,9 +5,11 @@ import (
 	"strings"
 
 	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/docker/builder"
 	"github.com/docker/docker/distribution"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/engine-api/types"
@@ -15,8 +17,11 @@ import (
 )
 
 // PullImage initiates a pull operation. image is the repository name to pull, and
-// tag may be either empty, or indicate a specific tag to pull.
-func (daemon *Daemon) PullImage(ctx context.Context, image, tag string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+// tag may be either empty, or indicate a specific tag to pull. platform may be
+// either empty, or select the os/arch[/variant] to pull from a manifest list.
+// maxBandwidth limits the bandwidth of the pull in bytes per second, if it
+// isn't 0.
+func (daemon *Daemon) PullImage(ctx context.Context, image, tag, platform string, maxBandwidth int64, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	// Special case: "pull -a" may send an image name with a
 	// trailing :. This is ugly, but let's not break API
 	// compatibility.
@@ -41,7 +46,15 @@ func (daemon *Daemon) PullImage(ctx context.Context, image, tag string, metaHead
 		}
 	}
 
-	return daemon.pullImageWithReference(ctx, ref, metaHeaders, authConfig, outStream)
+	var platformSpec *manifestlist.PlatformSpec
+	if platform != "" {
+		platformSpec, err = distribution.ParsePlatform(platform)
+		if err != nil {
+			return err
+		}
+	}
+
+	return daemon.pullImage(ctx, ref, platformSpec, maxBandwidth, metaHeaders, authConfig, outStream)
 }
 
 // PullOnBuild tells Docker to pull image referenced by `name`.
@@ -67,13 +80,22 @@ func (daemon *Daemon) PullOnBuild(ctx context.Context, name string, authConfigs
 		pullRegistryAuth = &resolvedConfig
 	}
 
-	if err := daemon.pullImageWithReference(ctx, ref, nil, pullRegistryAuth, output); err != nil {
+	if err := daemon.pullImage(ctx, ref, nil, 0, nil, pullRegistryAuth, output); err != nil {
 		return nil, err
 	}
 	return daemon.GetImage(name)
 }
 
-func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.Named, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+// pullImage pulls ref, enforcing the trust policy if it covers the
+// repository of ref.
+func (daemon *Daemon) pullImage(ctx context.Context, ref reference.Named, platform *manifestlist.PlatformSpec, maxBandwidth int64, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+	if daemon.trustPolicy.Covers(ref) {
+		return daemon.pullTrusted(ctx, ref, platform, maxBandwidth, metaHeaders, authConfig, outStream)
+	}
+	return daemon.pullImageWithReference(ctx, ref, platform, maxBandwidth, metaHeaders, authConfig, outStream)
+}
+
+func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.Named, platform *manifestlist.PlatformSpec, maxBandwidth int64, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	// Include a buffer so that slow client connections don't affect
 	// transfer performance.
 	progressChan := make(chan progress.Progress, 100)
@@ -97,6 +119,9 @@ func (daemon *Daemon) pullImageWithReference(ctx context.Context, ref reference.
 		ImageStore:       daemon.imageStore,
 		ReferenceStore:   daemon.referenceStore,
 		DownloadManager:  daemon.downloadManager,
+		Platform:         platform,
+		StagingStore:     daemon.stagingStore,
+		BandwidthLimiter: ratelimit.NewLimiter(maxBandwidth),
 	}
 
 	err := distribution.Pull(ctx, ref, imagePullConfig)
@@ -5,13 +5,16 @@ import (
 
 	"github.com/docker/docker/distribution"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/reference"
 	"github.com/docker/engine-api/types"
 	"golang.org/x/net/context"
 )
 
 // PushImage initiates a push operation on the repository named localName.
-func (daemon *Daemon) PushImage(ctx context.Context, image, tag string, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+// maxBandwidth limits the bandwidth of the push in bytes per second, if it
+// isn't 0.
+func (daemon *Daemon) PushImage(ctx context.Context, image, tag string, maxBandwidth int64, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
 	ref, err := reference.ParseNamed(image)
 	if err != nil {
 		return err
@@ -49,6 +52,7 @@ func (daemon *Daemon) PushImage(ctx context.Context, image, tag string, metaHead
 		ReferenceStore:   daemon.referenceStore,
 		TrustKey:         daemon.trustKey,
 		UploadManager:    daemon.uploadManager,
+		BandwidthLimiter: ratelimit.NewLimiter(maxBandwidth),
 	}
 
 	err = distribution.Push(ctx, ref, imagePushConfig)
@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"io"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/docker/daemon/trust"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+// pullTrusted pulls the images of ref signed according to the trust policy.
+// Tagged images are pulled by the digest signed for their tag, and tagged
+// once pulled.
+func (daemon *Daemon) pullTrusted(ctx context.Context, ref reference.Named, platform *manifestlist.PlatformSpec, maxBandwidth int64, metaHeaders map[string][]string, authConfig *types.AuthConfig, outStream io.Writer) error {
+	var targets []trust.Target
+	switch r := ref.(type) {
+	case reference.Canonical:
+		err := daemon.trustPolicy.VerifyDigests(ctx, ref, []digest.Digest{r.Digest()}, authConfig)
+		if err != nil {
+			return daemon.logUntrusted(err, ref.String(), ref.Name(), "pull")
+		}
+		return daemon.pullImageWithReference(ctx, ref, platform, maxBandwidth, metaHeaders, authConfig, outStream)
+	case reference.NamedTagged:
+		trustedRef, err := daemon.trustPolicy.ResolveTag(ctx, r, authConfig)
+		if err != nil {
+			return daemon.logUntrusted(err, ref.String(), ref.Name(), "pull")
+		}
+		targets = []trust.Target{{Tag: r.Tag(), Digest: trustedRef.Digest()}}
+	default:
+		var err error
+		targets, err = daemon.trustPolicy.Targets(ctx, ref, authConfig)
+		if err != nil {
+			return err
+		}
+		if len(targets) == 0 {
+			err := trust.NotTrustedError{Name: ref.String(), Reason: "no trusted tags"}
+			return daemon.logUntrusted(err, ref.String(), ref.Name(), "pull")
+		}
+	}
+
+	name, err := reference.WithName(ref.Name())
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		trustedRef, err := reference.WithDigest(name, t.Digest)
+		if err != nil {
+			return err
+		}
+		if err := daemon.pullImageWithReference(ctx, trustedRef, platform, maxBandwidth, metaHeaders, authConfig, outStream); err != nil {
+			return err
+		}
+		imgID, err := daemon.referenceStore.Get(trustedRef)
+		if err != nil {
+			return err
+		}
+		tagged, err := reference.WithTag(name, t.Tag)
+		if err != nil {
+			return err
+		}
+		if err := daemon.TagImageWithReference(imgID, tagged); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyImageTrust checks that the image id is signed according to the trust
+// policy in each repository it is known by. The image must have been pulled
+// by a signed digest in the repositories the policy covers.
+func (daemon *Daemon) verifyImageTrust(ctx context.Context, id image.ID, operation string) error {
+	if daemon.trustPolicy == nil {
+		return nil
+	}
+	names := make(map[string]reference.Named)
+	for _, ref := range daemon.referenceStore.References(id) {
+		names[ref.FullName()] = ref
+	}
+	for _, ref := range names {
+		if err := daemon.verifyImageTrustIn(ctx, ref, id, operation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verifyImageTrustIn checks that the image id is signed according to the
+// trust policy in the repository of ref.
+func (daemon *Daemon) verifyImageTrustIn(ctx context.Context, ref reference.Named, id image.ID, operation string) error {
+	return daemon.verifyDigestsIn(ctx, ref, id, daemon.pulledDigests(ref, id), operation)
+}
+
+func (daemon *Daemon) verifyDigestsIn(ctx context.Context, ref reference.Named, id image.ID, digests []digest.Digest, operation string) error {
+	name, err := reference.WithName(ref.Name())
+	if err != nil {
+		return err
+	}
+	if err := daemon.trustPolicy.VerifyDigests(ctx, name, digests, nil); err != nil {
+		return daemon.logUntrusted(err, id.String(), ref.String(), operation)
+	}
+	return nil
+}
+
+// pulledDigests returns the digests the image id was pulled by in the
+// repository of ref.
+func (daemon *Daemon) pulledDigests(ref reference.Named, id image.ID) []digest.Digest {
+	var digests []digest.Digest
+	for _, r := range daemon.referenceStore.References(id) {
+		if canonical, ok := r.(reference.Canonical); ok && r.FullName() == ref.FullName() {
+			digests = append(digests, canonical.Digest())
+		}
+	}
+	return digests
+}
+
+// logUntrusted logs an untrusted event if err is a refusal of the trust
+// policy, and returns err.
+func (daemon *Daemon) logUntrusted(err error, imageID, refName, operation string) error {
+	if _, ok := err.(trust.NotTrustedError); ok {
+		daemon.LogImageEventWithAttributes(imageID, refName, "untrusted", map[string]string{"operation": operation})
+	}
+	return err
+}
+
+// trustedReferenceStore is a reference store which only tags images signed
+// according to the trust policy in the repositories it covers.
+type trustedReferenceStore struct {
+	reference.Store
+	daemon *Daemon
+}
+
+// AddTag tags an image loaded from an archive. An archive doesn't carry the
+// manifests the images were pushed with, and their digests can't be computed
+// from the loaded content, so an image is only tagged in a covered repository
+// if it was pulled by a signed digest there before.
+func (s *trustedReferenceStore) AddTag(ref reference.Named, id image.ID, force bool) error {
+	digests := s.daemon.pulledDigests(ref, id)
+	if err := s.daemon.verifyDigestsIn(context.Background(), ref, id, digests, "load"); err != nil {
+		if e, ok := err.(trust.NotTrustedError); ok && len(digests) == 0 {
+			e.Reason = "images loaded from an archive have no signed digest, pull the image from its registry instead"
+			return e
+		}
+		return err
+	}
+	return s.Store.AddTag(ref, id, force)
+}
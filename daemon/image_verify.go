@@ -0,0 +1,111 @@
+package daemon
+
+import (
+	"strings"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/engine-api/types"
+)
+
+// VerifyImages verifies the integrity of the layers of the images names
+// refer to, or of all images if names is empty. Each corrupt layer is
+// reported with all the images using it.
+func (daemon *Daemon) VerifyImages(names []string) (*types.ImageVerifyResponse, error) {
+	var ids []image.ID
+	if len(names) == 0 {
+		for id := range daemon.imageStore.Map() {
+			ids = append(ids, id)
+		}
+	}
+	for _, name := range names {
+		id, err := daemon.GetImageID(name)
+		if err != nil {
+			return nil, daemon.imageNotExistToErrcode(err)
+		}
+		ids = append(ids, id)
+	}
+	return daemon.verifyLayers(ids), nil
+}
+
+// verifyLayers verifies the layers of the images ids, each layer once.
+func (daemon *Daemon) verifyLayers(ids []image.ID) *types.ImageVerifyResponse {
+	report := &types.ImageVerifyResponse{}
+	verified := make(map[layer.ChainID]struct{})
+	corrupt := make(map[layer.ChainID]*types.CorruptLayer)
+	var corruptOrder []layer.ChainID
+
+	for _, id := range ids {
+		img, err := daemon.imageStore.Get(id)
+		if err != nil {
+			// The image was deleted since it was listed.
+			continue
+		}
+		for i, chainID := range imageChainIDs(img) {
+			if _, exists := verified[chainID]; exists {
+				continue
+			}
+			verified[chainID] = struct{}{}
+			report.Layers++
+			if err := daemon.verifyLayer(chainID); err != nil {
+				corrupt[chainID] = &types.CorruptLayer{
+					ChainID: chainID.String(),
+					DiffID:  img.RootFS.DiffIDs[i].String(),
+					Error:   err.Error(),
+				}
+				corruptOrder = append(corruptOrder, chainID)
+			}
+		}
+	}
+	if len(corrupt) == 0 {
+		return report
+	}
+
+	// Report all the images using a corrupt layer, not only the ones
+	// which were verified.
+	for id, img := range daemon.imageStore.Map() {
+		for _, chainID := range imageChainIDs(img) {
+			if c, ok := corrupt[chainID]; ok {
+				c.Images = append(c.Images, id.String())
+			}
+		}
+	}
+	for _, chainID := range corruptOrder {
+		report.Corrupt = append(report.Corrupt, *corrupt[chainID])
+	}
+	return report
+}
+
+// verifyLayer verifies the integrity of the layer chainID.
+func (daemon *Daemon) verifyLayer(chainID layer.ChainID) error {
+	l, err := daemon.layerStore.Get(chainID)
+	if err != nil {
+		return err
+	}
+	defer layer.ReleaseAndLog(daemon.layerStore, l)
+	return layer.Verify(l)
+}
+
+// verifyLayersOnStartup verifies the layers of all images, and logs the
+// corrupt ones.
+func (daemon *Daemon) verifyLayersOnStartup() {
+	logrus.Info("Verifying the integrity of image layers")
+	report, _ := daemon.VerifyImages(nil)
+	for _, c := range report.Corrupt {
+		logrus.Errorf("Layer %s is corrupt: %s. Images using it: %s", c.ChainID, c.Error, strings.Join(c.Images, ", "))
+	}
+	logrus.Infof("Verified %d image layers, %d corrupt", report.Layers, len(report.Corrupt))
+}
+
+// imageChainIDs returns the chain IDs of the layers of img, from its base
+// layer to its top layer.
+func imageChainIDs(img *image.Image) []layer.ChainID {
+	rootFS := *img.RootFS
+	var chainIDs []layer.ChainID
+	for i := range img.RootFS.DiffIDs {
+		rootFS.DiffIDs = img.RootFS.DiffIDs[:i+1]
+		chainIDs = append(chainIDs, rootFS.ChainID())
+	}
+	return chainIDs
+}
@@ -116,6 +116,7 @@ func (daemon *Daemon) SystemInfo() (*types.Info, error) {
 		HTTPSProxy:         sockets.GetProxyEnv("https_proxy"),
 		NoProxy:            sockets.GetProxyEnv("no_proxy"),
 		SecurityOptions:    securityOptions,
+		Runtimes:           daemon.getRuntimes(),
 	}
 
 	// TODO Windows. Refactor this more once sysinfo is refactored into
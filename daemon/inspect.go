@@ -6,6 +6,7 @@ import (
 
 	"github.com/docker/docker/api/types/backend"
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/daemon/exec"
 	"github.com/docker/docker/daemon/network"
 	"github.com/docker/docker/pkg/version"
 	"github.com/docker/engine-api/types"
@@ -130,6 +131,16 @@ func (daemon *Daemon) getInspectData(container *container.Container, size bool)
 		Error:      container.State.Error,
 		StartedAt:  container.State.StartedAt.Format(time.RFC3339Nano),
 		FinishedAt: container.State.FinishedAt.Format(time.RFC3339Nano),
+		Usage:      container.State.Usage,
+	}
+
+	if rp := container.HostConfig.RestartPolicy; !rp.IsNone() && rp.Name != "" {
+		if state := container.RestartBackoff(); state != nil {
+			containerState.RestartBackoff = &types.RestartBackoff{
+				Delay:            state.Delay,
+				RestartsInWindow: state.RestartsInWindow,
+			}
+		}
 	}
 
 	contJSONBase := &types.ContainerJSONBase{
@@ -180,7 +191,10 @@ func (daemon *Daemon) ContainerExecInspect(id string) (*backend.ExecInspect, err
 	if err != nil {
 		return nil, err
 	}
+	return execInspect(e), nil
+}
 
+func execInspect(e *exec.Config) *backend.ExecInspect {
 	pc := inspectExecProcessConfig(e)
 
 	return &backend.ExecInspect{
@@ -194,7 +208,7 @@ func (daemon *Daemon) ContainerExecInspect(id string) (*backend.ExecInspect, err
 		CanRemove:     e.CanRemove,
 		ContainerID:   e.ContainerID,
 		DetachKeys:    e.DetachKeys,
-	}, nil
+	}
 }
 
 // VolumeInspect looks up a volume by name. An error is returned if
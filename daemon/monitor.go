@@ -32,9 +32,12 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		c.Wait()
 		c.Reset(false)
 		c.SetStopped(platformConstructExitStatus(e))
+		c.Usage = daemon.exitUsage(c)
+		daemon.seccompAuditor.remove(c.ID)
 		attributes := map[string]string{
 			"exitCode": strconv.Itoa(int(e.ExitCode)),
 		}
+		usageAttributes(attributes, c.Usage)
 		daemon.LogContainerEventWithAttributes(c, "die", attributes)
 		daemon.Cleanup(c)
 		// FIXME: here is race condition between two RUN instructions in Dockerfile
@@ -50,9 +53,11 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		c.Reset(false)
 		c.RestartCount++
 		c.SetRestarting(platformConstructExitStatus(e))
+		c.Usage = daemon.exitUsage(c)
 		attributes := map[string]string{
 			"exitCode": strconv.Itoa(int(e.ExitCode)),
 		}
+		usageAttributes(attributes, c.Usage)
 		daemon.LogContainerEventWithAttributes(c, "die", attributes)
 		if err := c.ToDisk(); err != nil {
 			return err
@@ -78,6 +83,10 @@ func (daemon *Daemon) StateChanged(id string, e libcontainerd.StateInfo) error {
 		}
 	case libcontainerd.StateStart, libcontainerd.StateRestore:
 		c.SetRunning(int(e.Pid), e.State == libcontainerd.StateStart)
+		if err := daemon.usageMonitor.add(c.ID, int(e.Pid)); err != nil {
+			logrus.Debugf("The resources used by %s won't be recorded: %v", c.ID, err)
+		}
+		daemon.seccompAuditor.started(c.ID, int(e.Pid))
 		c.HasBeenManuallyStopped = false
 		if err := c.ToDisk(); err != nil {
 			c.Reset(false)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -435,6 +436,27 @@ var (
 	}
 )
 
+// setInit bind-mounts the init binary into the container and makes it the
+// process run as PID 1, with the container command as its child, when
+// --init was requested for the container or enabled on the daemon.
+func setInit(daemon *Daemon, s *specs.Spec, c *container.Container) error {
+	if (c.HostConfig.Init == nil && !daemon.configStore.Init) || (c.HostConfig.Init != nil && !*c.HostConfig.Init) {
+		return nil
+	}
+	path, err := exec.LookPath(daemon.configStore.GetInitPath())
+	if err != nil {
+		return fmt.Errorf("could not find init binary: %v", err)
+	}
+	s.Process.Args = append([]string{"/dev/init", "--"}, s.Process.Args...)
+	s.Mounts = append(s.Mounts, specs.Mount{
+		Destination: "/dev/init",
+		Type:        "bind",
+		Source:      path,
+		Options:     []string{"bind", "ro"},
+	})
+	return nil
+}
+
 func setMounts(daemon *Daemon, s *specs.Spec, c *container.Container, mounts []container.Mount) error {
 	userMounts := make(map[string]struct{})
 	for _, m := range mounts {
@@ -640,10 +662,17 @@ func (daemon *Daemon) createSpec(c *container.Container) (*libcontainerd.Spec, e
 		return nil, err
 	}
 	mounts = append(mounts, c.IpcMounts()...)
-	mounts = append(mounts, c.TmpfsMounts()...)
+	tmpfsMounts, err := c.TmpfsMounts()
+	if err != nil {
+		return nil, err
+	}
+	mounts = append(mounts, tmpfsMounts...)
 	if err := setMounts(daemon, &s, c, mounts); err != nil {
 		return nil, fmt.Errorf("linux mounts: %v", err)
 	}
+	if err := setInit(daemon, &s, c); err != nil {
+		return nil, fmt.Errorf("linux init: %v", err)
+	}
 
 	for _, ns := range s.Linux.Namespaces {
 		if ns.Type == "network" && ns.Path == "" && !c.Config.NetworkDisabled {
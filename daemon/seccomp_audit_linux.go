@@ -0,0 +1,370 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/errors"
+	"github.com/docker/docker/pkg/audit"
+	"github.com/docker/docker/pkg/procconnector"
+	"github.com/docker/docker/profiles/seccomp"
+	"github.com/docker/engine-api/types"
+)
+
+const (
+	// unmatchedRecordDelay is how long a record whose process isn't known
+	// to belong to a container is kept, in case the fork of the process is
+	// received after the record.
+	unmatchedRecordDelay = 100 * time.Millisecond
+	// exitedProcessDelay is how long an exited process is still matched to
+	// its container, in case its last records are received after its exit.
+	exitedProcessDelay = 5 * time.Second
+)
+
+// seccompAuditor learns the system calls used by the containers running in
+// seccomp audit mode, from the records the kernel logs for them. The records
+// are matched to the containers by PID. The processes of the containers are
+// followed as they fork, starting from their init process, so that the
+// records of processes which already exited can be matched too.
+type seccompAuditor struct {
+	sync.Mutex
+	daemon       *Daemon
+	listener     *audit.Listener
+	procListener *procconnector.Listener
+	// syscalls holds the system calls learned for each container, by
+	// container ID.
+	syscalls map[string]map[string]struct{}
+	// pids holds the container of each process of the containers, by PID.
+	pids map[int]string
+	// support holds why containers can't be run in seccomp audit mode with
+	// each runtime, or nil if they can, by path of the runtime.
+	support map[string]error
+}
+
+// newSeccompAuditor creates the seccomp auditor of the daemon, checking which
+// of its runtimes support seccomp audit mode.
+func newSeccompAuditor(daemon *Daemon) *seccompAuditor {
+	a := &seccompAuditor{
+		daemon:   daemon,
+		syscalls: make(map[string]map[string]struct{}),
+		pids:     make(map[int]string),
+		support:  make(map[string]error),
+	}
+	kernelErr := checkSeccompLogAction()
+	for _, rt := range daemon.configStore.Runtimes {
+		if kernelErr != nil {
+			a.support[rt.Path] = kernelErr
+		} else {
+			a.support[rt.Path] = checkRuntimeSeccompLogAction(rt.Path)
+		}
+	}
+	return a
+}
+
+// checkSupport returns an error if containers can't be run in seccomp audit
+// mode with the runtime of the given name.
+func (a *seccompAuditor) checkSupport(runtime string) error {
+	rt := a.daemon.configStore.GetRuntime(runtime)
+	if rt == nil {
+		return fmt.Errorf("No such runtime '%s'", runtime)
+	}
+	return a.support[rt.Path]
+}
+
+// seccompActionsAvail lists the seccomp actions supported by the kernel.
+const seccompActionsAvail = "/proc/sys/kernel/seccomp/actions_avail"
+
+// checkSeccompLogAction returns an error if the kernel doesn't support the
+// log action the audit profile is made of.
+func checkSeccompLogAction() error {
+	if b, err := ioutil.ReadFile(seccompActionsAvail); err == nil {
+		for _, action := range strings.Fields(string(b)) {
+			if action == "log" {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("The kernel doesn't support the log action of seccomp, cannot run in seccomp audit mode. Linux 4.14 or later is required.")
+}
+
+// checkRuntimeSeccompLogAction returns an error if the runtime at path doesn't
+// support the SCMP_ACT_LOG action the audit profile is made of. The runtimes
+// which support it have its name in their binary, to parse the profile.
+func checkRuntimeSeccompLogAction(path string) error {
+	p, err := exec.LookPath(path)
+	if err == nil {
+		var b []byte
+		if b, err = ioutil.ReadFile(p); err == nil && bytes.Contains(b, []byte(seccomp.ActLog)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("The container runtime %s doesn't support the %s seccomp action, cannot run in seccomp audit mode.", path, seccomp.ActLog)
+}
+
+// add starts learning the system calls used by a container, in addition to
+// those learned during its previous runs.
+func (a *seccompAuditor) add(c *container.Container) error {
+	a.Lock()
+	defer a.Unlock()
+
+	if a.listener == nil {
+		l, err := audit.NewListener()
+		if err != nil {
+			return fmt.Errorf("Cannot receive the kernel audit records for seccomp audit mode: %v", err)
+		}
+		a.listener = l
+		go a.run(l)
+	}
+	if a.procListener == nil {
+		l, err := procconnector.NewListener()
+		if err != nil {
+			// The records are still matched by the cgroups of their
+			// processes, as long as they're running.
+			logrus.Warnf("Cannot receive the process forks from the kernel, the system calls of short-lived processes won't be learned in seccomp audit mode: %v", err)
+		} else {
+			a.procListener = l
+			go a.runProcesses(l)
+		}
+	}
+
+	names, err := readLearnedSyscalls(c)
+	if err != nil {
+		return err
+	}
+	syscalls := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		syscalls[name] = struct{}{}
+	}
+	a.syscalls[c.ID] = syscalls
+	return nil
+}
+
+// restore keeps learning the system calls used by a running container after
+// the daemon restarted, if it runs in seccomp audit mode.
+func (a *seccompAuditor) restore(c *container.Container) {
+	if c.SeccompProfile != "audit" {
+		return
+	}
+	if err := a.add(c); err != nil {
+		logrus.Warnf("Failed to keep learning the system calls of %s in seccomp audit mode: %v", c.ID, err)
+	}
+}
+
+// remove stops learning the system calls used by a container, once it exited.
+func (a *seccompAuditor) remove(id string) {
+	a.Lock()
+	defer a.Unlock()
+	delete(a.syscalls, id)
+	for pid, cid := range a.pids {
+		if cid == id {
+			delete(a.pids, pid)
+		}
+	}
+}
+
+func (a *seccompAuditor) run(l *audit.Listener) {
+	for {
+		msgs, err := l.Receive()
+		if err != nil {
+			if err == syscall.ENOBUFS {
+				logrus.Warn("Kernel audit records were lost, the system calls learned in seccomp audit mode may be incomplete")
+				continue
+			}
+			logrus.Errorf("Error receiving kernel audit records: %v", err)
+			a.Lock()
+			a.listener = nil
+			a.Unlock()
+			l.Close()
+			return
+		}
+
+		for _, m := range msgs {
+			if m.Type != audit.TypeSeccomp {
+				continue
+			}
+			r, err := audit.ParseSeccompRecord(m.Data)
+			if err != nil {
+				logrus.Debugf("Ignoring kernel audit record: %v", err)
+				continue
+			}
+			if !a.record(r) {
+				time.AfterFunc(unmatchedRecordDelay, func() {
+					if !a.record(r) {
+						logrus.Warnf("Dropping the seccomp audit record of system call %d of process %d, which isn't known to belong to a container in seccomp audit mode", r.Syscall, r.Pid)
+					}
+				})
+			}
+		}
+	}
+}
+
+// runProcesses follows the forks and exits of the processes of the
+// containers.
+func (a *seccompAuditor) runProcesses(l *procconnector.Listener) {
+	for {
+		events, err := l.Receive()
+		if err != nil {
+			if err == syscall.ENOBUFS {
+				logrus.Warn("Process forks were lost, the system calls learned in seccomp audit mode may be incomplete")
+				continue
+			}
+			logrus.Errorf("Error receiving process forks: %v", err)
+			a.Lock()
+			a.procListener = nil
+			a.Unlock()
+			l.Close()
+			return
+		}
+
+		a.Lock()
+		for _, ev := range events {
+			switch ev.Type {
+			case procconnector.Fork:
+				if id, ok := a.pids[ev.ParentTgid]; ok {
+					a.pids[ev.Tgid] = id
+				}
+			case procconnector.Exit:
+				if id, ok := a.pids[ev.Tgid]; ok && ev.Pid == ev.Tgid {
+					a.expire(ev.Tgid, id)
+				}
+			}
+		}
+		a.Unlock()
+	}
+}
+
+// expire forgets an exited process once its last records had time to be
+// received. The lock must be held.
+func (a *seccompAuditor) expire(pid int, id string) {
+	time.AfterFunc(exitedProcessDelay, func() {
+		a.Lock()
+		if a.pids[pid] == id {
+			delete(a.pids, pid)
+		}
+		a.Unlock()
+	})
+}
+
+// started starts following the processes of a container from its init
+// process, if it runs in seccomp audit mode.
+func (a *seccompAuditor) started(id string, pid int) {
+	a.Lock()
+	if _, ok := a.syscalls[id]; ok {
+		a.pids[pid] = id
+	}
+	a.Unlock()
+}
+
+// containerOf returns the container in seccomp audit mode of a process, if
+// any. Processes which weren't followed from the init process of their
+// container, such as those run by docker exec, are matched by their cgroups
+// while they're running. The lock must be held.
+func (a *seccompAuditor) containerOf(pid int) (string, bool) {
+	if id, ok := a.pids[pid]; ok {
+		return id, true
+	}
+	cgroups, err := ioutil.ReadFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return "", false
+	}
+	for id := range a.syscalls {
+		if bytes.Contains(cgroups, []byte(id)) {
+			a.pids[pid] = id
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// record adds the system call of a seccomp record to the system calls learned
+// for the container of its process. It returns false if the process isn't
+// known to belong to a container in seccomp audit mode.
+func (a *seccompAuditor) record(r *audit.SeccompRecord) bool {
+	a.Lock()
+	defer a.Unlock()
+	id, ok := a.containerOf(r.Pid)
+	if !ok {
+		return false
+	}
+	syscalls := a.syscalls[id]
+
+	c := a.daemon.containers.Get(id)
+	if c == nil {
+		delete(a.syscalls, id)
+		return true
+	}
+	name, err := syscallName(r.Arch, r.Syscall)
+	if err != nil {
+		logrus.Warnf("Cannot learn system call %d of %s: %v", r.Syscall, id, err)
+		return true
+	}
+	if _, exists := syscalls[name]; exists {
+		return true
+	}
+	syscalls[name] = struct{}{}
+
+	names := make([]string, 0, len(syscalls))
+	for name := range syscalls {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if err := writeLearnedSyscalls(c, names); err != nil {
+		logrus.Errorf("Error saving the system calls learned for %s: %v", id, err)
+	}
+	return true
+}
+
+func readLearnedSyscalls(c *container.Container) ([]string, error) {
+	b, err := ioutil.ReadFile(c.SeccompAuditPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	if err := json.Unmarshal(b, &names); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func writeLearnedSyscalls(c *container.Container, names []string) error {
+	b, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.SeccompAuditPath(), b, 0600)
+}
+
+// ContainerSeccompProfile returns a seccomp profile which only allows the
+// system calls used by a container run in seccomp audit mode.
+func (daemon *Daemon) ContainerSeccompProfile(name string) (*types.Seccomp, error) {
+	c, err := daemon.GetContainer(name)
+	if err != nil {
+		return nil, err
+	}
+	if c.SeccompProfile != "audit" {
+		err := fmt.Errorf("Container %s was not run with --security-opt seccomp=audit", c.ID)
+		return nil, errors.NewBadRequestError(err)
+	}
+
+	daemon.seccompAuditor.Lock()
+	names, err := readLearnedSyscalls(c)
+	daemon.seccompAuditor.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	return seccomp.ProfileFromSyscalls(names), nil
+}
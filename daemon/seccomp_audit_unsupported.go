@@ -0,0 +1,30 @@
+// +build !linux
+
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/engine-api/types"
+)
+
+type seccompAuditor struct{}
+
+func newSeccompAuditor(daemon *Daemon) *seccompAuditor {
+	return &seccompAuditor{}
+}
+
+func (a *seccompAuditor) restore(c *container.Container) {
+}
+
+func (a *seccompAuditor) started(id string, pid int) {
+}
+
+func (a *seccompAuditor) remove(id string) {
+}
+
+// ContainerSeccompProfile is not supported on this platform.
+func (daemon *Daemon) ContainerSeccompProfile(name string) (*types.Seccomp, error) {
+	return nil, fmt.Errorf("Seccomp is not supported on this platform")
+}
@@ -3,10 +3,19 @@
 package daemon
 
 import (
+	"fmt"
+
 	"github.com/docker/docker/container"
 	"github.com/opencontainers/specs/specs-go"
 )
 
 func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
+	if c.SeccompProfile == "audit" {
+		return fmt.Errorf("Seccomp is not supported by this daemon, cannot run in seccomp audit mode.")
+	}
 	return nil
 }
+
+func syscallName(arch uint32, nr int) (string, error) {
+	return "", fmt.Errorf("seccomp is not supported by this daemon")
+}
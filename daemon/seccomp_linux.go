@@ -9,6 +9,7 @@ import (
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/profiles/seccomp"
 	"github.com/opencontainers/specs/specs-go"
+	libseccomp "github.com/seccomp/libseccomp-golang"
 )
 
 func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
@@ -29,7 +30,18 @@ func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
 	if c.SeccompProfile == "unconfined" {
 		return nil
 	}
-	if c.SeccompProfile != "" {
+	if c.SeccompProfile == "audit" {
+		if err := daemon.seccompAuditor.checkSupport(c.HostConfig.Runtime); err != nil {
+			return err
+		}
+		profile, err = seccomp.GetAuditProfile()
+		if err != nil {
+			return err
+		}
+		if err := daemon.seccompAuditor.add(c); err != nil {
+			return err
+		}
+	} else if c.SeccompProfile != "" {
 		profile, err = seccomp.LoadProfile(c.SeccompProfile)
 		if err != nil {
 			return err
@@ -44,3 +56,35 @@ func setSeccomp(daemon *Daemon, rs *specs.Spec, c *container.Container) error {
 	rs.Linux.Seccomp = profile
 	return nil
 }
+
+// auditArches maps the architectures of the kernel audit records,
+// AUDIT_ARCH_* in linux/audit.h, to those of libseccomp.
+var auditArches = map[uint32]libseccomp.ScmpArch{
+	0x40000003: libseccomp.ArchX86,
+	0xc000003e: libseccomp.ArchAMD64,
+	0x40000028: libseccomp.ArchARM,
+	0xc00000b7: libseccomp.ArchARM64,
+	0x00000008: libseccomp.ArchMIPS,
+	0x80000008: libseccomp.ArchMIPS64,
+	0xa0000008: libseccomp.ArchMIPS64N32,
+	0x40000008: libseccomp.ArchMIPSEL,
+	0xc0000008: libseccomp.ArchMIPSEL64,
+	0xe0000008: libseccomp.ArchMIPSEL64N32,
+}
+
+// x32SyscallBit is set in the numbers of the x32 system calls, which the
+// kernel audit records report with the x86_64 architecture.
+const x32SyscallBit = 0x40000000
+
+// syscallName returns the name of a system call reported by a kernel audit
+// record.
+func syscallName(arch uint32, nr int) (string, error) {
+	scmpArch, ok := auditArches[arch]
+	if !ok {
+		return "", fmt.Errorf("unknown architecture %x", arch)
+	}
+	if scmpArch == libseccomp.ArchAMD64 && nr&x32SyscallBit != 0 {
+		scmpArch = libseccomp.ArchX32
+	}
+	return libseccomp.ScmpSyscall(nr).GetNameByArch(scmpArch)
+}
@@ -15,8 +15,9 @@ import (
 	containertypes "github.com/docker/engine-api/types/container"
 )
 
-// ContainerStart starts a container.
-func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.HostConfig) error {
+// ContainerStart starts a container. If checkpoint is set, the container
+// is restored from that checkpoint instead of being started afresh.
+func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.HostConfig, checkpoint string) error {
 	container, err := daemon.GetContainer(name)
 	if err != nil {
 		return err
@@ -31,6 +32,12 @@ func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.Hos
 		return errors.NewErrorWithStatusCode(err, http.StatusNotModified)
 	}
 
+	if checkpoint != "" {
+		if err := checkpointExists(container, checkpoint); err != nil {
+			return err
+		}
+	}
+
 	// Windows does not have the backwards compatibility issue here.
 	if runtime.GOOS != "windows" {
 		// This is kept for backward compatibility - hostconfig should be passed when
@@ -72,19 +79,19 @@ func (daemon *Daemon) ContainerStart(name string, hostConfig *containertypes.Hos
 		return err
 	}
 
-	return daemon.containerStart(container)
+	return daemon.containerStart(container, checkpoint)
 }
 
 // Start starts a container
 func (daemon *Daemon) Start(container *container.Container) error {
-	return daemon.containerStart(container)
+	return daemon.containerStart(container, "")
 }
 
 // containerStart prepares the container to run by setting up everything the
 // container needs, such as storage and networking, as well as links
 // between containers. The container is left waiting for a signal to
 // begin running.
-func (daemon *Daemon) containerStart(container *container.Container) (err error) {
+func (daemon *Daemon) containerStart(container *container.Container, checkpoint string) (err error) {
 	container.Lock()
 	defer container.Unlock()
 
@@ -131,7 +138,13 @@ func (daemon *Daemon) containerStart(container *container.Container) (err error)
 		return err
 	}
 
-	if err := daemon.containerd.Create(container.ID, *spec, libcontainerd.WithRestartManager(container.RestartManager(true))); err != nil {
+	createOptions, err := daemon.getLibcontainerdCreateOptions(container, checkpoint)
+	if err != nil {
+		return err
+	}
+	createOptions = append(createOptions, libcontainerd.WithRestartManager(container.RestartManager(true)))
+
+	if err := daemon.containerd.Create(container.ID, *spec, createOptions...); err != nil {
 		// if we receive an internal error from the initial start of a container then lets
 		// return it instead of entering the restart loop
 		// set to 127 for container cmd not found/does not exist)
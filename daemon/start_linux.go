@@ -0,0 +1,27 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/libcontainerd"
+)
+
+// getLibcontainerdCreateOptions returns the platform specific options
+// the container is created with in containerd, restoring it from
+// checkpoint if one is given.
+func (daemon *Daemon) getLibcontainerdCreateOptions(container *container.Container, checkpoint string) ([]libcontainerd.CreateOption, error) {
+	createOptions := []libcontainerd.CreateOption{}
+
+	rt := daemon.configStore.GetRuntime(container.HostConfig.Runtime)
+	if rt == nil {
+		return nil, fmt.Errorf("No such runtime '%s'", container.HostConfig.Runtime)
+	}
+	createOptions = append(createOptions, libcontainerd.WithRuntime(rt.Path, rt.Args))
+
+	if checkpoint != "" {
+		createOptions = append(createOptions, libcontainerd.WithCheckpoint(checkpoint, container.CheckpointDir()))
+	}
+
+	return createOptions, nil
+}
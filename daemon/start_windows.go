@@ -0,0 +1,18 @@
+package daemon
+
+import (
+	"fmt"
+
+	"github.com/docker/docker/container"
+	"github.com/docker/docker/libcontainerd"
+)
+
+// getLibcontainerdCreateOptions returns the platform specific options
+// the container is created with. There are none on Windows, and
+// containers can't be restored from a checkpoint.
+func (daemon *Daemon) getLibcontainerdCreateOptions(container *container.Container, checkpoint string) ([]libcontainerd.CreateOption, error) {
+	if checkpoint != "" {
+		return nil, fmt.Errorf("Windows: Containers do not support checkpoints")
+	}
+	return []libcontainerd.CreateOption{}, nil
+}
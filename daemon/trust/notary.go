@@ -0,0 +1,172 @@
+package trust
+
+import (
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/registry/client/auth"
+	"github.com/docker/distribution/registry/client/transport"
+	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"github.com/docker/go-connections/tlsconfig"
+	"github.com/docker/notary/client"
+	"github.com/docker/notary/tuf/data"
+	"golang.org/x/net/context"
+)
+
+var releasesRole = path.Join(data.CanonicalTargetsRole, "releases")
+
+// NotaryResolver looks up the trust data of repositories on notary servers.
+type NotaryResolver struct {
+	trustDir        string
+	registryService *registry.Service
+}
+
+// NewNotaryResolver returns a NotaryResolver caching trust data in
+// trustDir. The registries of repositories are resolved by registryService.
+func NewNotaryResolver(trustDir string, registryService *registry.Service) *NotaryResolver {
+	return &NotaryResolver{
+		trustDir:        trustDir,
+		registryService: registryService,
+	}
+}
+
+type credentialStore struct {
+	auth types.AuthConfig
+}
+
+func (cs credentialStore) Basic(u *url.URL) (string, string) {
+	return cs.auth.Username, cs.auth.Password
+}
+
+func (cs credentialStore) RefreshToken(u *url.URL, service string) string {
+	return cs.auth.IdentityToken
+}
+
+func (cs credentialStore) SetRefreshToken(*url.URL, string, string) {
+}
+
+// Targets returns the targets of the releases and targets roles of the
+// repository name, with the IDs of the keys which signed their role.
+func (r *NotaryResolver) Targets(ctx context.Context, name reference.Named, server string, authConfig *types.AuthConfig) ([]Target, error) {
+	repoInfo, err := r.registryService.ResolveRepository(name)
+	if err != nil {
+		return nil, err
+	}
+	if server == "" {
+		if repoInfo.Index.Official {
+			server = registry.NotaryServer
+		} else {
+			server = "https://" + repoInfo.Index.Name
+		}
+	}
+
+	notaryRepo, err := r.notaryRepository(ctx, repoInfo, server, authConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	notaryTargets, err := notaryRepo.ListTargets(releasesRole, data.CanonicalTargetsRole)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := notaryRepo.ListRoles()
+	if err != nil {
+		return nil, err
+	}
+	signers := make(map[string][]string)
+	for _, role := range roles {
+		for _, sig := range role.Signatures {
+			signers[role.Name] = append(signers[role.Name], sig.KeyID)
+		}
+	}
+
+	var targets []Target
+	for _, t := range notaryTargets {
+		h, ok := t.Hashes["sha256"]
+		if !ok {
+			logrus.Debugf("Ignoring target %s of %s without a sha256 hash", t.Name, repoInfo.FullName())
+			continue
+		}
+		targets = append(targets, Target{
+			Tag:    t.Name,
+			Digest: digest.NewDigestFromHex("sha256", hex.EncodeToString(h)),
+			KeyIDs: signers[t.Role],
+		})
+	}
+	return targets, nil
+}
+
+// notaryRepository returns the notary repository of repoInfo on server,
+// authenticated with authConfig.
+func (r *NotaryResolver) notaryRepository(ctx context.Context, repoInfo *registry.RepositoryInfo, server string, authConfig *types.AuthConfig) (*client.NotaryRepository, error) {
+	cfg, err := tlsconfig.Client(tlsconfig.Options{InsecureSkipVerify: !repoInfo.Index.Secure})
+	if err != nil {
+		return nil, err
+	}
+
+	u, err := url.Parse(server)
+	if err != nil {
+		return nil, err
+	}
+	if err := registry.ReadCertsDirectory(cfg, filepath.Join(registry.CertsDir, u.Host)); err != nil {
+		return nil, err
+	}
+
+	base := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		Dial: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			DualStack: true,
+		}).Dial,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     cfg,
+		DisableKeepAlives:   true,
+	}
+
+	modifiers := registry.DockerHeaders(dockerversion.DockerUserAgent(ctx), http.Header{})
+	authTransport := transport.NewTransport(base, modifiers...)
+	pingClient := &http.Client{
+		Transport: authTransport,
+		Timeout:   5 * time.Second,
+	}
+	endpointStr := server + "/v2/"
+	req, err := http.NewRequest("GET", endpointStr, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	challengeManager := auth.NewSimpleChallengeManager()
+
+	resp, err := pingClient.Do(req)
+	if err != nil {
+		// Ignore error on ping to use the cached trust data
+		logrus.Debugf("Error pinging notary server %q: %s", endpointStr, err)
+	} else {
+		defer resp.Body.Close()
+		if err := challengeManager.AddResponse(resp); err != nil {
+			return nil, err
+		}
+	}
+
+	creds := credentialStore{}
+	if authConfig != nil {
+		creds.auth = *authConfig
+	}
+	tokenHandler := auth.NewTokenHandler(authTransport, creds, repoInfo.FullName(), "pull")
+	basicHandler := auth.NewBasicHandler(creds)
+	modifiers = append(modifiers, transport.RequestModifier(auth.NewAuthorizer(challengeManager, tokenHandler, basicHandler)))
+	tr := transport.NewTransport(base, modifiers...)
+
+	return client.NewNotaryRepository(r.trustDir, repoInfo.FullName(), server, tr, nil)
+}
@@ -0,0 +1,226 @@
+// Package trust implements the content trust policy of the daemon. The
+// policy lists the repositories whose images must be signed, and the keys
+// they must be signed by, so that it is enforced whichever client pulls,
+// loads or runs an image.
+package trust
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/reference"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+// A Rule requires the images of the repositories it matches to be signed.
+type Rule struct {
+	// Name is the name of a repository, such as docker.io/library/busybox,
+	// or a prefix of repository names followed by /*, such as
+	// docker.io/library/*. A Name of * matches all repositories.
+	Name string `json:"name"`
+	// Keys are the IDs of the keys allowed to sign the images. If there
+	// are none, images signed by any key of the trust data are allowed.
+	Keys []string `json:"keys,omitempty"`
+	// Server is the URL of the notary server of the repositories, which
+	// defaults to the notary server of their registry.
+	Server string `json:"server,omitempty"`
+
+	prefix string
+}
+
+// Policy is the trust policy of the daemon. Its rules are matched in order,
+// and the images of repositories no rule matches don't need to be signed.
+type Policy struct {
+	Repositories []*Rule `json:"repositories"`
+
+	resolver Resolver
+}
+
+// A Target is an image digest signed for a tag in the trust data of a
+// repository.
+type Target struct {
+	Tag    string
+	Digest digest.Digest
+	// KeyIDs are the IDs of the keys which signed the role holding the
+	// target.
+	KeyIDs []string
+}
+
+// A Resolver looks up the trust data of repositories.
+type Resolver interface {
+	// Targets returns the signed targets of the repository name, from
+	// the trust data on server.
+	Targets(ctx context.Context, name reference.Named, server string, authConfig *types.AuthConfig) ([]Target, error)
+}
+
+// NotTrustedError is returned for images the trust policy refuses.
+type NotTrustedError struct {
+	Name   string
+	Reason string
+}
+
+func (e NotTrustedError) Error() string {
+	return fmt.Sprintf("image %s is refused by the trust policy: %s", e.Name, e.Reason)
+}
+
+// HTTPErrorStatusCode returns the status code of refused images, 403.
+func (e NotTrustedError) HTTPErrorStatusCode() int {
+	return http.StatusForbidden
+}
+
+// LoadPolicy reads the policy in the JSON file at path. The trust data of
+// the repositories is looked up with resolver.
+func LoadPolicy(path string, resolver Resolver) (*Policy, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	p := &Policy{resolver: resolver}
+	if err := json.NewDecoder(f).Decode(p); err != nil {
+		return nil, fmt.Errorf("invalid trust policy %s: %v", path, err)
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid trust policy %s: %v", path, err)
+	}
+	return p, nil
+}
+
+// validate checks the rules of p, and normalizes their names.
+func (p *Policy) validate() error {
+	for _, rule := range p.Repositories {
+		if rule == nil {
+			return fmt.Errorf("empty rule")
+		}
+		if rule.Server != "" && !strings.HasPrefix(rule.Server, "https://") {
+			return fmt.Errorf("valid https URL required for the trust server of %s, got %s", rule.Name, rule.Server)
+		}
+		if rule.Name == "*" {
+			rule.prefix = ""
+			continue
+		}
+		name := strings.TrimSuffix(rule.Name, "/*")
+		named, err := reference.ParseNamed(name)
+		if err != nil {
+			return fmt.Errorf("invalid repository name %q: %v", rule.Name, err)
+		}
+		if !reference.IsNameOnly(named) {
+			return fmt.Errorf("invalid repository name %q: tags and digests aren't allowed", rule.Name)
+		}
+		if name != rule.Name {
+			rule.prefix = named.FullName() + "/"
+		} else {
+			rule.prefix = named.FullName()
+		}
+	}
+	return nil
+}
+
+// rule returns the first rule matching the repository name, or nil if
+// there is none.
+func (p *Policy) rule(name reference.Named) *Rule {
+	if p == nil {
+		return nil
+	}
+	fullName := name.FullName()
+	for _, rule := range p.Repositories {
+		if strings.HasSuffix(rule.prefix, "/") || rule.prefix == "" {
+			if strings.HasPrefix(fullName, rule.prefix) {
+				return rule
+			}
+		} else if fullName == rule.prefix {
+			return rule
+		}
+	}
+	return nil
+}
+
+// Covers returns whether the images of the repository name must be signed.
+// A nil Policy covers no repository.
+func (p *Policy) Covers(name reference.Named) bool {
+	return p.rule(name) != nil
+}
+
+// Targets returns the targets of the repository name which are signed by
+// keys the policy allows. It returns no target if the policy doesn't cover
+// the repository.
+func (p *Policy) Targets(ctx context.Context, name reference.Named, authConfig *types.AuthConfig) ([]Target, error) {
+	rule := p.rule(name)
+	if rule == nil {
+		return nil, nil
+	}
+	targets, err := p.resolver.Targets(ctx, name, rule.Server, authConfig)
+	if err != nil {
+		return nil, err
+	}
+	var trusted []Target
+	for _, t := range targets {
+		if rule.trusts(t) {
+			trusted = append(trusted, t)
+		}
+	}
+	return trusted, nil
+}
+
+// ResolveTag returns the reference by digest of the image signed for the
+// tag of ref. It returns nil if the policy doesn't cover the repository of
+// ref.
+func (p *Policy) ResolveTag(ctx context.Context, ref reference.NamedTagged, authConfig *types.AuthConfig) (reference.Canonical, error) {
+	if !p.Covers(ref) {
+		return nil, nil
+	}
+	targets, err := p.Targets(ctx, ref, authConfig)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range targets {
+		if t.Tag == ref.Tag() {
+			return reference.WithDigest(ref, t.Digest)
+		}
+	}
+	return nil, NotTrustedError{Name: ref.String(), Reason: "no trusted signature for the tag"}
+}
+
+// VerifyDigests checks that one of digests, the digests an image is known
+// by in the repository name, is signed by a key the policy allows.
+func (p *Policy) VerifyDigests(ctx context.Context, name reference.Named, digests []digest.Digest, authConfig *types.AuthConfig) error {
+	if !p.Covers(name) {
+		return nil
+	}
+	if len(digests) == 0 {
+		return NotTrustedError{Name: name.String(), Reason: "the image wasn't pulled by a signed digest"}
+	}
+	targets, err := p.Targets(ctx, name, authConfig)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		for _, dgst := range digests {
+			if t.Digest == dgst {
+				return nil
+			}
+		}
+	}
+	return NotTrustedError{Name: name.String(), Reason: fmt.Sprintf("no trusted signature for %s", digests[0])}
+}
+
+// trusts returns whether t is signed by a key the rule allows.
+func (r *Rule) trusts(t Target) bool {
+	if len(r.Keys) == 0 {
+		return true
+	}
+	for _, keyID := range t.KeyIDs {
+		for _, allowed := range r.Keys {
+			if keyID == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package trust
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/reference"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+type fakeResolver map[string][]Target
+
+func (r fakeResolver) Targets(ctx context.Context, name reference.Named, server string, authConfig *types.AuthConfig) ([]Target, error) {
+	return r[name.FullName()], nil
+}
+
+func loadTestPolicy(t *testing.T, policy string, resolver Resolver) (*Policy, error) {
+	tmpDir, err := ioutil.TempDir("", "trust-policy-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+	path := filepath.Join(tmpDir, "policy.json")
+	if err := ioutil.WriteFile(path, []byte(policy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return LoadPolicy(path, resolver)
+}
+
+func parseNamed(t *testing.T, name string) reference.Named {
+	named, err := reference.ParseNamed(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return named
+}
+
+func TestLoadPolicyInvalid(t *testing.T) {
+	for _, policy := range []string{
+		`{"repositories": [{"name": "Invalid"}]}`,
+		`{"repositories": [{"name": "busybox:latest"}]}`,
+		`{"repositories": [{"name": "busybox", "server": "http://notary.example.com"}]}`,
+		`{"repositories": [null]}`,
+		`{"repositories": {}}`,
+	} {
+		if _, err := loadTestPolicy(t, policy, nil); err == nil {
+			t.Fatalf("Expected policy %s to be invalid", policy)
+		}
+	}
+}
+
+func TestPolicyCovers(t *testing.T) {
+	p, err := loadTestPolicy(t, `{"repositories": [
+		{"name": "busybox"},
+		{"name": "example.com/team/*"}
+	]}`, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, expected := range map[string]bool{
+		"busybox":                      true,
+		"docker.io/library/busybox":    true,
+		"busybox:latest":               true,
+		"busybox2":                     false,
+		"example.com/team/app":         true,
+		"example.com/team/group/app":   true,
+		"example.com/team":             false,
+		"example.com/teammate/app":     false,
+		"registry.example.com/busybox": false,
+	} {
+		if covers := p.Covers(parseNamed(t, name)); covers != expected {
+			t.Fatalf("Expected Covers(%s) to be %v, got %v", name, expected, covers)
+		}
+	}
+
+	var nilPolicy *Policy
+	if nilPolicy.Covers(parseNamed(t, "busybox")) {
+		t.Fatal("Expected a nil policy to cover no repository")
+	}
+}
+
+func TestPolicyResolveTag(t *testing.T) {
+	signed := digest.FromBytes([]byte("signed"))
+	other := digest.FromBytes([]byte("other"))
+	resolver := fakeResolver{
+		"docker.io/library/busybox": {
+			{Tag: "latest", Digest: signed, KeyIDs: []string{"trusted"}},
+			{Tag: "other", Digest: other, KeyIDs: []string{"unknown"}},
+		},
+	}
+	p, err := loadTestPolicy(t, `{"repositories": [{"name": "*", "keys": ["trusted"]}]}`, resolver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	ref, err := p.ResolveTag(ctx, parseNamed(t, "busybox:latest").(reference.NamedTagged), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref.Digest() != signed {
+		t.Fatalf("Expected busybox:latest to resolve to %s, got %s", signed, ref.Digest())
+	}
+
+	// A tag signed by a key the policy doesn't allow is refused.
+	for _, name := range []string{"busybox:other", "busybox:missing"} {
+		_, err := p.ResolveTag(ctx, parseNamed(t, name).(reference.NamedTagged), nil)
+		if _, ok := err.(NotTrustedError); !ok {
+			t.Fatalf("Expected %s to be refused, got %v", name, err)
+		}
+	}
+
+	if err := p.VerifyDigests(ctx, parseNamed(t, "busybox"), []digest.Digest{other, signed}, nil); err != nil {
+		t.Fatalf("Expected a signed digest to be trusted, got %v", err)
+	}
+	for _, digests := range [][]digest.Digest{nil, {other}} {
+		err := p.VerifyDigests(ctx, parseNamed(t, "busybox"), digests, nil)
+		if _, ok := err.(NotTrustedError); !ok {
+			t.Fatalf("Expected digests %v to be refused, got %v", digests, err)
+		}
+	}
+}
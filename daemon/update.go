@@ -71,8 +71,14 @@ func (daemon *Daemon) update(name string, hostConfig *container.HostConfig) erro
 		return errCannotUpdate(container.ID, err)
 	}
 
+	// the restart policy is merged with the container's, check the result
+	if err := validateRestartPolicy(container.HostConfig.RestartPolicy, false); err != nil {
+		restoreConfig = true
+		return errCannotUpdate(container.ID, err)
+	}
+
 	// if Restart Policy changed, we need to update container monitor
-	container.UpdateMonitor(hostConfig.RestartPolicy)
+	container.UpdateMonitor(container.HostConfig.RestartPolicy)
 
 	// if container is restarting, wait 5 seconds until it's running
 	if container.IsRestarting() {
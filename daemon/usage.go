@@ -0,0 +1,54 @@
+package daemon
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/container"
+	"github.com/docker/engine-api/types"
+)
+
+// exitUsageTimeout is how long the usage of a container read as it exited is
+// waited for, in case the daemon is notified of the exit first.
+const exitUsageTimeout = time.Second
+
+func setNetworkUsage(usage *types.ContainerUsage, networks map[string]types.NetworkStats) {
+	usage.NetworkRxBytes, usage.NetworkTxBytes = 0, 0
+	for _, n := range networks {
+		usage.NetworkRxBytes += n.RxBytes
+		usage.NetworkTxBytes += n.TxBytes
+	}
+}
+
+// usageAttributes adds the resource usage of a container to the attributes
+// of its die event.
+func usageAttributes(attributes map[string]string, usage *types.ContainerUsage) {
+	if usage == nil {
+		return
+	}
+	attributes["cpuTime"] = strconv.FormatUint(usage.CPUTime, 10)
+	attributes["memoryMaxUsage"] = strconv.FormatUint(usage.MemoryMaxUsage, 10)
+	attributes["blkioReadBytes"] = strconv.FormatUint(usage.BlkioReadBytes, 10)
+	attributes["blkioWriteBytes"] = strconv.FormatUint(usage.BlkioWriteBytes, 10)
+	attributes["networkRxBytes"] = strconv.FormatUint(usage.NetworkRxBytes, 10)
+	attributes["networkTxBytes"] = strconv.FormatUint(usage.NetworkTxBytes, 10)
+}
+
+// exitUsage returns the resource usage of a container which just exited: its
+// cgroup stats read as its init process exited, with the totals of its
+// network interfaces which are still around until the container is cleaned
+// up. It returns nil if its cgroup stats weren't read.
+func (daemon *Daemon) exitUsage(c *container.Container) *types.ContainerUsage {
+	usage := daemon.usageMonitor.wait(c.ID, exitUsageTimeout)
+	if usage == nil {
+		return nil
+	}
+	networks, err := daemon.getNetworkStats(c)
+	if err != nil {
+		logrus.Debugf("collecting network usage of %s: %v", c.ID, err)
+		return usage
+	}
+	setNetworkUsage(usage, networks)
+	return usage
+}
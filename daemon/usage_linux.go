@@ -0,0 +1,253 @@
+package daemon
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/procconnector"
+	"github.com/docker/engine-api/types"
+	"github.com/opencontainers/runc/libcontainer/cgroups"
+)
+
+// usageMonitor reads the resource usage of the containers from their cgroups
+// as their init process exits. containerd removes the cgroups of a container
+// before the daemon is notified that it exited, so the exits are received
+// from the proc connector of the kernel instead, as they happen.
+type usageMonitor struct {
+	sync.Mutex
+	listener *procconnector.Listener
+	// listenerFailed is set once the failure to receive the process exits
+	// has been reported, until they're received again.
+	listenerFailed bool
+	// watches holds the running containers, by PID of their init process.
+	watches map[int]*usageWatch
+}
+
+// usageWatch is a running container whose resource usage is read as its
+// init process exits.
+type usageWatch struct {
+	cgroups *usageCgroups
+	exited  bool
+	// done is closed once the exit of the init process was handled, usage
+	// is nil if it couldn't be read.
+	done  chan struct{}
+	usage *types.ContainerUsage
+}
+
+// usageCgroups are the cgroup directories the resource usage of a container
+// is read from.
+type usageCgroups struct {
+	id      string
+	cpuacct string
+	memory  string
+	blkio   string
+}
+
+func newUsageMonitor() *usageMonitor {
+	return &usageMonitor{watches: make(map[int]*usageWatch)}
+}
+
+// add starts watching for the exit of the init process of a container.
+func (m *usageMonitor) add(id string, pid int) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if m.listener == nil {
+		l, err := procconnector.NewListener()
+		if err != nil {
+			if !m.listenerFailed {
+				logrus.Warnf("Cannot receive the process exits from the kernel, the resources used by containers won't be recorded: %v", err)
+				m.listenerFailed = true
+			}
+			return fmt.Errorf("Cannot receive the process exits from the kernel: %v", err)
+		}
+		m.listener = l
+		m.listenerFailed = false
+		go m.run(l)
+	}
+
+	cg, err := containerCgroups(id, pid)
+	if err != nil {
+		return err
+	}
+	m.removeLocked(id)
+	m.watches[pid] = &usageWatch{cgroups: cg, done: make(chan struct{})}
+	return nil
+}
+
+// wait stops watching a container once it's known to have exited, and
+// returns the resource usage read as its init process exited. The reading
+// is waited for at most timeout, in case the exit is received from the
+// kernel after containerd reported it. It returns nil if the container
+// wasn't watched, or if its usage couldn't be read in time.
+func (m *usageMonitor) wait(id string, timeout time.Duration) *types.ContainerUsage {
+	m.Lock()
+	w := m.removeLocked(id)
+	m.Unlock()
+	if w == nil {
+		return nil
+	}
+
+	select {
+	case <-w.done:
+		return w.usage
+	case <-time.After(timeout):
+		logrus.Warnf("The exit of %s wasn't received from the kernel, the resources it used aren't recorded", id)
+		return nil
+	}
+}
+
+// removeLocked stops watching a container and returns its watch, if any.
+func (m *usageMonitor) removeLocked(id string) *usageWatch {
+	for pid, w := range m.watches {
+		if w.cgroups.id == id {
+			delete(m.watches, pid)
+			return w
+		}
+	}
+	return nil
+}
+
+func (m *usageMonitor) run(l *procconnector.Listener) {
+	for {
+		events, err := l.Receive()
+		if err != nil {
+			if err == syscall.ENOBUFS {
+				logrus.Warn("Process exits were lost, the resources used by containers may not be recorded")
+				continue
+			}
+			logrus.Errorf("Error receiving process exits: %v", err)
+			m.Lock()
+			m.listener = nil
+			m.Unlock()
+			l.Close()
+			return
+		}
+
+		for _, ev := range events {
+			// Only the exit of the whole process matters, not of
+			// its other threads.
+			if ev.Type == procconnector.Exit && ev.Pid == ev.Tgid {
+				m.exited(ev.Pid)
+			}
+		}
+	}
+}
+
+// exited reads the resource usage of the container whose init process
+// exited, if any.
+func (m *usageMonitor) exited(pid int) {
+	m.Lock()
+	w := m.watches[pid]
+	if w == nil || w.exited {
+		m.Unlock()
+		return
+	}
+	w.exited = true
+	m.Unlock()
+	w.read()
+}
+
+// read reads the resource usage of the container of the watch and signals
+// that it's done.
+func (w *usageWatch) read() {
+	defer close(w.done)
+	usage, err := w.cgroups.usage()
+	if err != nil {
+		logrus.Warnf("Cannot read the resources used by %s: %v", w.cgroups.id, err)
+		return
+	}
+	w.usage = usage
+}
+
+// containerCgroups returns the cgroup directories of the container with the
+// init process pid.
+func containerCgroups(id string, pid int) (*usageCgroups, error) {
+	paths, err := cgroups.ParseCgroupFile(fmt.Sprintf("/proc/%d/cgroup", pid))
+	if err != nil {
+		return nil, err
+	}
+	dir := func(subsystem string) (string, error) {
+		p, ok := paths[subsystem]
+		if !ok {
+			return "", fmt.Errorf("no %s cgroup for process %d", subsystem, pid)
+		}
+		mnt, root, err := cgroups.FindCgroupMountpointAndRoot(subsystem)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(mnt, strings.TrimPrefix(p, root)), nil
+	}
+
+	cg := &usageCgroups{id: id}
+	if cg.cpuacct, err = dir("cpuacct"); err != nil {
+		return nil, err
+	}
+	if cg.memory, err = dir("memory"); err != nil {
+		return nil, err
+	}
+	if cg.blkio, err = dir("blkio"); err != nil {
+		return nil, err
+	}
+	return cg, nil
+}
+
+// usage reads the totals of the resource usage of the container from its
+// cgroups.
+func (cg *usageCgroups) usage() (*types.ContainerUsage, error) {
+	var (
+		usage types.ContainerUsage
+		err   error
+	)
+	if usage.CPUTime, err = readCgroupValue(filepath.Join(cg.cpuacct, "cpuacct.usage")); err != nil {
+		return nil, err
+	}
+	if usage.MemoryMaxUsage, err = readCgroupValue(filepath.Join(cg.memory, "memory.max_usage_in_bytes")); err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(filepath.Join(cg.blkio, "blkio.io_service_bytes_recursive"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	// Each line is "major:minor op bytes", except the last "Total bytes".
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) != 3 {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[2], 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		switch strings.ToLower(fields[1]) {
+		case "read":
+			usage.BlkioReadBytes += v
+		case "write":
+			usage.BlkioWriteBytes += v
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func readCgroupValue(path string) (uint64, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+}
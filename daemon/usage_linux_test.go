@@ -0,0 +1,71 @@
+package daemon
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestUsageCgroups(t *testing.T) {
+	dir, err := ioutil.TempDir("", "usage-cgroups")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"cpuacct.usage":                    "1500000000\n",
+		"memory.max_usage_in_bytes":        "4096\n",
+		"blkio.io_service_bytes_recursive": "8:0 Read 100\n8:0 Write 200\n8:0 Sync 300\n8:0 Async 0\n8:0 Total 300\n8:16 Read 10\n8:16 Write 20\nTotal 330\n",
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cg := &usageCgroups{id: "c1", cpuacct: dir, memory: dir, blkio: dir}
+	usage, err := cg.usage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := types.ContainerUsage{
+		CPUTime:         1500000000,
+		MemoryMaxUsage:  4096,
+		BlkioReadBytes:  110,
+		BlkioWriteBytes: 220,
+	}
+	if *usage != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, *usage)
+	}
+
+	// The usage read as the init process exits is returned once the
+	// container is known to have exited.
+	m := newUsageMonitor()
+	m.watches[42] = &usageWatch{cgroups: cg, done: make(chan struct{})}
+	m.exited(42)
+	if usage := m.wait("c1", time.Second); usage == nil || *usage != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, usage)
+	}
+	if len(m.watches) != 0 {
+		t.Fatalf("Expected the container to be forgotten, got %v", m.watches)
+	}
+
+	// No usage is returned if the exit isn't received in time.
+	m.watches[42] = &usageWatch{cgroups: cg, done: make(chan struct{})}
+	if usage := m.wait("c1", time.Millisecond); usage != nil {
+		t.Fatalf("Expected no usage, got %+v", usage)
+	}
+
+	// Nor once the cgroups are removed.
+	m.watches[42] = &usageWatch{cgroups: cg, done: make(chan struct{})}
+	os.RemoveAll(dir)
+	m.exited(42)
+	if usage := m.wait("c1", time.Second); usage != nil {
+		t.Fatalf("Expected no usage, got %+v", usage)
+	}
+}
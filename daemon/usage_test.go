@@ -0,0 +1,29 @@
+package daemon
+
+import (
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestUsageAttributes(t *testing.T) {
+	attributes := map[string]string{}
+	usageAttributes(attributes, nil)
+	if len(attributes) != 0 {
+		t.Fatalf("Expected no attributes without usage, got %v", attributes)
+	}
+
+	usage := &types.ContainerUsage{
+		CPUTime:         1500000000,
+		MemoryMaxUsage:  4096,
+		BlkioWriteBytes: 220,
+	}
+	setNetworkUsage(usage, map[string]types.NetworkStats{
+		"eth0": {RxBytes: 1000, TxBytes: 2000},
+		"eth1": {RxBytes: 1, TxBytes: 2},
+	})
+	usageAttributes(attributes, usage)
+	if attributes["cpuTime"] != "1500000000" || attributes["blkioWriteBytes"] != "220" || attributes["networkRxBytes"] != "1001" || attributes["networkTxBytes"] != "2002" {
+		t.Fatalf("Unexpected die event attributes: %v", attributes)
+	}
+}
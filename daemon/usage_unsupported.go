@@ -0,0 +1,23 @@
+// +build !linux
+
+package daemon
+
+import (
+	"time"
+
+	"github.com/docker/engine-api/types"
+)
+
+type usageMonitor struct{}
+
+func newUsageMonitor() *usageMonitor {
+	return &usageMonitor{}
+}
+
+func (m *usageMonitor) add(id string, pid int) error {
+	return nil
+}
+
+func (m *usageMonitor) wait(id string, timeout time.Duration) *types.ContainerUsage {
+	return nil
+}
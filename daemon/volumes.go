@@ -8,9 +8,11 @@ import (
 	"strings"
 
 	"github.com/docker/docker/container"
+	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/volume"
 	"github.com/docker/engine-api/types"
 	containertypes "github.com/docker/engine-api/types/container"
+	mounttypes "github.com/docker/engine-api/types/mount"
 	"github.com/opencontainers/runc/libcontainer/label"
 )
 
@@ -64,7 +66,8 @@ func (m mounts) parts(i int) int {
 // 1. Select the previously configured mount points for the containers, if any.
 // 2. Select the volumes mounted from another containers. Overrides previously configured mount point destination.
 // 3. Select the bind mounts set by the client. Overrides previously configured mount point destinations.
-// 4. Cleanup old volumes that are about to be reassigned.
+// 4. Select the mounts set by the client with the structured mount API. Overrides previously configured mount point destinations.
+// 5. Cleanup old volumes that are about to be reassigned.
 func (daemon *Daemon) registerMountPoints(container *container.Container, hostConfig *containertypes.HostConfig) error {
 	binds := map[string]bool{}
 	mountPoints := map[string]*volume.MountPoint{}
@@ -117,10 +120,12 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 			return err
 		}
 
-		if binds[bind.Destination] {
+		_, tmpfsExists := hostConfig.Tmpfs[bind.Destination]
+		if binds[bind.Destination] || tmpfsExists {
 			return fmt.Errorf("Duplicate mount point '%s'", bind.Destination)
 		}
 
+		bind.Type = mounttypes.TypeBind
 		if len(bind.Name) > 0 {
 			// create the volume
 			v, err := daemon.volumes.CreateWithRef(bind.Name, bind.Driver, container.ID, nil, nil)
@@ -132,6 +137,7 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 			// bind.Name is an already existing volume, we need to use that here
 			bind.Driver = v.DriverName()
 			bind.Named = true
+			bind.Type = mounttypes.TypeVolume
 			if bind.Driver == "local" {
 				bind = setBindModeIfNull(bind)
 			}
@@ -146,9 +152,53 @@ func (daemon *Daemon) registerMountPoints(container *container.Container, hostCo
 		mountPoints[bind.Destination] = bind
 	}
 
+	// 4. Read mounts from the structured mount API
+	for _, cfg := range hostConfig.Mounts {
+		mp, err := volume.ParseMountConfig(cfg, hostConfig.VolumeDriver)
+		if err != nil {
+			return err
+		}
+
+		_, tmpfsExists := hostConfig.Tmpfs[mp.Destination]
+		if binds[mp.Destination] || tmpfsExists {
+			return fmt.Errorf("Duplicate mount point '%s'", cfg.Target)
+		}
+
+		if mp.Type == mounttypes.TypeVolume {
+			if len(mp.Name) == 0 {
+				mp.Name = stringid.GenerateNonCryptoID()
+			}
+			var driverOpts, labels map[string]string
+			if cfg.VolumeOptions != nil {
+				labels = cfg.VolumeOptions.Labels
+				if cfg.VolumeOptions.DriverConfig != nil {
+					driverOpts = cfg.VolumeOptions.DriverConfig.Options
+				}
+			}
+			v, err := daemon.volumes.CreateWithRef(mp.Name, mp.Driver, container.ID, driverOpts, labels)
+			if err != nil {
+				return err
+			}
+			mp.Volume = v
+			mp.Source = v.Path()
+			mp.Driver = v.DriverName()
+			if mp.Driver == "local" {
+				mp = setBindModeIfNull(mp)
+			}
+			if label.RelabelNeeded(mp.Mode) {
+				if err := label.Relabel(mp.Source, container.MountLabel, label.IsShared(mp.Mode)); err != nil {
+					return err
+				}
+			}
+		}
+
+		binds[mp.Destination] = true
+		mountPoints[mp.Destination] = mp
+	}
+
 	container.Lock()
 
-	// 4. Cleanup old volumes that are about to be reassigned.
+	// 5. Cleanup old volumes that are about to be reassigned.
 	for _, m := range mountPoints {
 		if m.BackwardsCompatible() {
 			if mp, exists := container.MountPoints[m.Destination]; exists && mp.Volume != nil {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/docker/docker/container"
 	"github.com/docker/docker/volume"
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 // setupMounts iterates through each of the mount points for a container and
@@ -17,6 +18,10 @@ import (
 func (daemon *Daemon) setupMounts(c *container.Container) ([]container.Mount, error) {
 	var mounts []container.Mount
 	for _, m := range c.MountPoints {
+		// tmpfs mounts are set up by the runtime, see TmpfsMounts.
+		if m.Type == mounttypes.TypeTmpfs {
+			continue
+		}
 		if err := daemon.lazyInitializeVolume(c.ID, m); err != nil {
 			return nil, err
 		}
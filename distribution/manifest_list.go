@@ -0,0 +1,339 @@
+package distribution
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution"
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/distribution/manifest/schema2"
+	distreference "github.com/docker/distribution/reference"
+	"github.com/docker/distribution/registry/client"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/reference"
+	"github.com/docker/docker/registry"
+	"github.com/docker/engine-api/types"
+	"golang.org/x/net/context"
+)
+
+// ManifestListConfig stores the configuration for looking up the manifests
+// referenced by a manifest list, and for pushing the manifest list.
+type ManifestListConfig struct {
+	// MetaHeaders store HTTP headers with metadata about the request
+	MetaHeaders map[string][]string
+	// AuthConfig holds authentication credentials for authenticating with
+	// the registry.
+	AuthConfig *types.AuthConfig
+	// RegistryService is the registry service to use for TLS configuration
+	// and endpoint lookup.
+	RegistryService *registry.Service
+}
+
+// ManifestListEntry is a manifest referenced by a manifest list, along with
+// the repository it was looked up in.
+type ManifestListEntry struct {
+	Repository reference.Named
+	Descriptor manifestlist.ManifestDescriptor
+}
+
+// GetManifestDescriptor looks up the manifest ref refers to in its registry,
+// and returns a manifest list descriptor for it. The platform of the
+// descriptor is read from the image configuration.
+func GetManifestDescriptor(ctx context.Context, ref reference.Named, config *ManifestListConfig) (manifestlist.ManifestDescriptor, error) {
+	var descriptor manifestlist.ManifestDescriptor
+	err := withV2Repository(ctx, ref, config, false, func(endpoint registry.APIEndpoint, repo distribution.Repository) error {
+		manSvc, err := repo.Manifests(ctx)
+		if err != nil {
+			return err
+		}
+
+		var manifest distribution.Manifest
+		if digested, isDigested := ref.(reference.Canonical); isDigested {
+			manifest, err = manSvc.Get(ctx, digested.Digest())
+		} else {
+			tag := reference.DefaultTag
+			if tagged, isTagged := ref.(reference.NamedTagged); isTagged {
+				tag = tagged.Tag()
+			}
+			manifest, err = manSvc.Get(ctx, "", distribution.WithTag(tag))
+		}
+		if err != nil {
+			return err
+		}
+
+		descriptor, err = manifestDescriptor(ctx, repo, manifest)
+		return err
+	})
+	return descriptor, err
+}
+
+// manifestDescriptor returns a manifest list descriptor for manifest.
+func manifestDescriptor(ctx context.Context, repo distribution.Repository, manifest distribution.Manifest) (manifestlist.ManifestDescriptor, error) {
+	mediaType, payload, err := manifest.Payload()
+	if err != nil {
+		return manifestlist.ManifestDescriptor{}, err
+	}
+	descriptor := manifestlist.ManifestDescriptor{
+		Descriptor: distribution.Descriptor{
+			MediaType: mediaType,
+			Digest:    digest.FromBytes(payload),
+			Size:      int64(len(payload)),
+		},
+	}
+
+	switch v := manifest.(type) {
+	case *schema2.DeserializedManifest:
+		configJSON, err := repo.Blobs(ctx).Get(ctx, v.Target().Digest)
+		if err != nil {
+			return manifestlist.ManifestDescriptor{}, err
+		}
+		var img image.Image
+		if err := json.Unmarshal(configJSON, &img); err != nil {
+			return manifestlist.ManifestDescriptor{}, err
+		}
+		descriptor.Platform = manifestlist.PlatformSpec{
+			OS:           img.OS,
+			Architecture: img.Architecture,
+			Variant:      img.Variant,
+			OSVersion:    img.OSVersion,
+			OSFeatures:   img.OSFeatures,
+		}
+	case *schema1.SignedManifest:
+		descriptor.Platform.Architecture = v.Architecture
+		if len(v.History) > 0 {
+			var img image.V1Image
+			if err := json.Unmarshal([]byte(v.History[0].V1Compatibility), &img); err != nil {
+				return manifestlist.ManifestDescriptor{}, err
+			}
+			descriptor.Platform.OS = img.OS
+		}
+	case *manifestlist.DeserializedManifestList:
+		return manifestlist.ManifestDescriptor{}, errors.New("a manifest list cannot reference another manifest list")
+	default:
+		return manifestlist.ManifestDescriptor{}, errors.New("unsupported manifest format")
+	}
+	return descriptor, nil
+}
+
+// PushManifestList pushes a manifest list referencing the manifests of
+// entries to ref, and returns its digest. A registry only accepts manifest
+// lists referencing manifests of the same repository, so manifests of other
+// repositories of the registry are copied to the repository of ref first.
+func PushManifestList(ctx context.Context, ref reference.NamedTagged, entries []ManifestListEntry, config *ManifestListConfig) (digest.Digest, error) {
+	repoInfo, err := config.RegistryService.ResolveRepository(ref)
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		descriptors = make([]manifestlist.ManifestDescriptor, 0, len(entries))
+		copies      []ManifestListEntry
+	)
+	for _, entry := range entries {
+		if entry.Descriptor.Platform.OS == "" || entry.Descriptor.Platform.Architecture == "" {
+			return "", fmt.Errorf("manifest %s@%s has no platform", entry.Repository.Name(), entry.Descriptor.Digest)
+		}
+		entryInfo, err := config.RegistryService.ResolveRepository(entry.Repository)
+		if err != nil {
+			return "", err
+		}
+		if entryInfo.Hostname() != repoInfo.Hostname() {
+			return "", fmt.Errorf("manifest %s@%s is not in registry %s", entry.Repository.Name(), entry.Descriptor.Digest, repoInfo.Hostname())
+		}
+		if entryInfo.FullName() != repoInfo.FullName() {
+			copies = append(copies, entry)
+		}
+		descriptors = append(descriptors, entry.Descriptor)
+	}
+
+	manifestList, err := manifestlist.FromDescriptors(descriptors)
+	if err != nil {
+		return "", err
+	}
+
+	var manifestDigest digest.Digest
+	err = withV2Repository(ctx, ref, config, true, func(endpoint registry.APIEndpoint, repo distribution.Repository) error {
+		for _, entry := range copies {
+			if err := copyManifest(ctx, endpoint, entry, repo, config); err != nil {
+				return err
+			}
+		}
+
+		manSvc, err := repo.Manifests(ctx)
+		if err != nil {
+			return err
+		}
+		manifestDigest, err = manSvc.Put(ctx, manifestList, distribution.WithTag(ref.Tag()))
+		return err
+	})
+	return manifestDigest, err
+}
+
+// copyManifest copies the manifest of entry, and the blobs it references,
+// from the repository of entry to repo.
+func copyManifest(ctx context.Context, endpoint registry.APIEndpoint, entry ManifestListEntry, repo distribution.Repository, config *ManifestListConfig) error {
+	srcInfo, err := config.RegistryService.ResolveRepository(entry.Repository)
+	if err != nil {
+		return err
+	}
+	srcRepo, _, err := NewV2Repository(ctx, srcInfo, endpoint, config.MetaHeaders, config.AuthConfig, "pull")
+	if err != nil {
+		if fallbackErr, ok := err.(fallbackError); ok {
+			err = fallbackErr.err
+		}
+		return err
+	}
+
+	srcManSvc, err := srcRepo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	manifest, err := srcManSvc.Get(ctx, entry.Descriptor.Digest)
+	if err != nil {
+		return err
+	}
+
+	for _, blob := range manifest.References() {
+		if err := copyBlob(ctx, srcRepo, repo, blob); err != nil {
+			return err
+		}
+	}
+
+	manSvc, err := repo.Manifests(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = manSvc.Put(ctx, manifest)
+	return err
+}
+
+// copyBlob copies a blob from srcRepo to repo, mounting it if the registry
+// allows it.
+func copyBlob(ctx context.Context, srcRepo, repo distribution.Repository, blob distribution.Descriptor) error {
+	bs := repo.Blobs(ctx)
+	if _, err := bs.Stat(ctx, blob.Digest); err == nil {
+		return nil
+	}
+
+	canonicalRef, err := distreference.WithDigest(srcRepo.Named(), blob.Digest)
+	if err != nil {
+		return err
+	}
+
+	logrus.Debugf("attempting to mount blob %s from %s", blob.Digest, srcRepo.Named().Name())
+
+	upload, err := bs.Create(ctx, client.WithMountFrom(canonicalRef))
+	switch err.(type) {
+	case distribution.ErrBlobMounted:
+		return nil
+	case nil:
+	default:
+		return err
+	}
+	defer upload.Cancel(ctx)
+
+	rc, err := srcRepo.Blobs(ctx).Open(ctx, blob.Digest)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if _, err := upload.ReadFrom(rc); err != nil {
+		return err
+	}
+	_, err = upload.Commit(ctx, blob)
+	return err
+}
+
+// withV2Repository calls fn with a client for the repository of ref, trying
+// each v2 endpoint of the registry in turn. Manifest lists are only supported
+// by the v2 protocol, so v1 endpoints are skipped.
+func withV2Repository(ctx context.Context, ref reference.Named, config *ManifestListConfig, push bool, fn func(registry.APIEndpoint, distribution.Repository) error) error {
+	repoInfo, err := config.RegistryService.ResolveRepository(ref)
+	if err != nil {
+		return err
+	}
+
+	// makes sure name is not empty or `scratch`
+	if err := validateRepoName(repoInfo.Name()); err != nil {
+		return err
+	}
+
+	var (
+		endpoints []registry.APIEndpoint
+		actions   = []string{"pull"}
+	)
+	if push {
+		endpoints, err = config.RegistryService.LookupPushEndpoints(repoInfo.Hostname())
+		actions = []string{"push", "pull"}
+	} else {
+		endpoints, err = config.RegistryService.LookupPullEndpoints(repoInfo.Hostname())
+	}
+	if err != nil {
+		return err
+	}
+
+	var (
+		lastErr error
+
+		// confirmedTLSRegistries is a map indicating which registries
+		// are known to be using TLS. There should never be a plaintext
+		// retry for any of these.
+		confirmedTLSRegistries = make(map[string]struct{})
+	)
+
+	for _, endpoint := range endpoints {
+		if endpoint.Version == registry.APIVersion1 {
+			continue
+		}
+
+		if endpoint.URL.Scheme != "https" {
+			if _, confirmedTLS := confirmedTLSRegistries[endpoint.URL.Host]; confirmedTLS {
+				logrus.Debugf("Skipping non-TLS endpoint %s for host/port that appears to use TLS", endpoint.URL)
+				continue
+			}
+		}
+
+		logrus.Debugf("Trying %s on %s", repoInfo.FullName(), endpoint.URL)
+
+		repo, _, err := NewV2Repository(ctx, repoInfo, endpoint, config.MetaHeaders, config.AuthConfig, actions...)
+		if err != nil {
+			if fallbackErr, ok := err.(fallbackError); ok {
+				if fallbackErr.transportOK && endpoint.URL.Scheme == "https" {
+					confirmedTLSRegistries[endpoint.URL.Host] = struct{}{}
+				}
+				lastErr = fallbackErr.err
+				continue
+			}
+			return err
+		}
+
+		if err := fn(endpoint, repo); err != nil {
+			// Was this request cancelled? If so, don't try to fall
+			// back.
+			select {
+			case <-ctx.Done():
+				return err
+			default:
+			}
+			// Pushes aren't retried, to avoid partially copying
+			// manifests to several endpoints.
+			if !push && continueOnError(err) {
+				lastErr = err
+				logrus.Errorf("Attempting next endpoint after error: %v", err)
+				continue
+			}
+			return err
+		}
+		return nil
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no v2 endpoints found for %s", repoInfo.FullName())
+	}
+	return lastErr
+}
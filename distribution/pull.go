@@ -2,13 +2,17 @@ package distribution
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/docker/api"
 	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/staging"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/engine-api/types"
@@ -39,6 +43,56 @@ type ImagePullConfig struct {
 	ReferenceStore reference.Store
 	// DownloadManager manages concurrent pulls.
 	DownloadManager *xfer.LayerDownloadManager
+	// Platform selects the entry to pull from a manifest list. If nil, the
+	// platform of the daemon is used.
+	Platform *manifestlist.PlatformSpec
+	// StagingStore keeps partially downloaded layers, so that a later pull
+	// can resume them. If nil, partial downloads are discarded.
+	StagingStore *staging.Store
+	// BandwidthLimiter limits the bandwidth of the pull, on top of the
+	// limit of DownloadManager. If nil, only the limit of DownloadManager
+	// applies.
+	BandwidthLimiter *ratelimit.Limiter
+}
+
+// limiters returns the limiters of the blob readers of the pull.
+func (config *ImagePullConfig) limiters() []*ratelimit.Limiter {
+	limiters := []*ratelimit.Limiter{config.BandwidthLimiter}
+	if config.DownloadManager != nil {
+		limiters = append(limiters, config.DownloadManager.BandwidthLimiter())
+	}
+	return limiters
+}
+
+// ParsePlatform parses a platform in the form os/arch[/variant], for example
+// linux/arm64 or linux/arm/v7.
+func ParsePlatform(platform string) (*manifestlist.PlatformSpec, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 {
+		return nil, fmt.Errorf("invalid platform %q, must be in the form os/arch[/variant]", platform)
+	}
+	for _, part := range parts {
+		if part == "" {
+			return nil, fmt.Errorf("invalid platform %q, must be in the form os/arch[/variant]", platform)
+		}
+	}
+	spec := &manifestlist.PlatformSpec{
+		OS:           strings.ToLower(parts[0]),
+		Architecture: strings.ToLower(parts[1]),
+	}
+	if len(parts) == 3 {
+		spec.Variant = strings.ToLower(parts[2])
+	}
+	return spec, nil
+}
+
+// formatPlatform returns the os/arch[/variant] form of platform.
+func formatPlatform(platform manifestlist.PlatformSpec) string {
+	s := platform.OS + "/" + platform.Architecture
+	if platform.Variant != "" {
+		s += "/" + platform.Variant
+	}
+	return s
 }
 
 // Puller is an interface that abstracts pulling for different API versions.
@@ -21,6 +21,7 @@ import (
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
@@ -226,6 +227,7 @@ func (p *v1Puller) pullImage(ctx context.Context, v1ID, endpoint string, localNa
 			layersDownloaded: layersDownloaded,
 			layerSize:        imgSize,
 			session:          p.session,
+			limiters:         p.config.limiters(),
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -284,6 +286,7 @@ type v1LayerDescriptor struct {
 	layersDownloaded *bool
 	layerSize        int64
 	session          *registry.Session
+	limiters         []*ratelimit.Limiter
 	tmpFile          *os.File
 }
 
@@ -320,7 +323,7 @@ func (ld *v1LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		return nil, 0, err
 	}
 
-	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, layerReader), progressOutput, ld.layerSize, ld.ID(), "Downloading")
+	reader := progress.NewProgressReader(ratelimit.NewReader(ctx, ioutils.NewCancelReadCloser(ctx, layerReader), ld.limiters...), progressOutput, ld.layerSize, ld.ID(), throttledAction("Downloading", ld.limiters))
 	defer reader.Close()
 
 	_, err = io.Copy(ld.tmpFile, reader)
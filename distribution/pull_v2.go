@@ -20,12 +20,14 @@ import (
 	"github.com/docker/distribution/registry/client/auth"
 	"github.com/docker/distribution/registry/client/transport"
 	"github.com/docker/docker/distribution/metadata"
+	"github.com/docker/docker/distribution/staging"
 	"github.com/docker/docker/distribution/xfer"
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/image/v1"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
@@ -131,8 +133,12 @@ type v2LayerDescriptor struct {
 	repoInfo          *registry.RepositoryInfo
 	repo              distribution.Repository
 	V2MetadataService *metadata.V2MetadataService
+	stagingStore      *staging.Store
+	limiters          []*ratelimit.Limiter
 	tmpFile           *os.File
-	verifier          digest.Verifier
+	// staged is set if tmpFile is a partial download in stagingStore.
+	staged   bool
+	verifier digest.Verifier
 }
 
 func (ld *v2LayerDescriptor) Key() string {
@@ -156,27 +162,28 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	)
 
 	if ld.tmpFile == nil {
-		ld.tmpFile, err = createDownloadFile()
+		ld.tmpFile, err = ld.openDownloadFile()
 		if err != nil {
 			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
-	} else {
-		offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
-		if err != nil {
-			logrus.Debugf("error seeking to end of download file: %v", err)
-			offset = 0
+	}
 
-			ld.tmpFile.Close()
-			if err := os.Remove(ld.tmpFile.Name()); err != nil {
-				logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
-			}
-			ld.tmpFile, err = createDownloadFile()
-			if err != nil {
-				return nil, 0, xfer.DoNotRetry{Err: err}
-			}
-		} else if offset != 0 {
-			logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
+	// A new download file may also hold a partial download left by an
+	// earlier pull in the staging store.
+	offset, err = ld.tmpFile.Seek(0, os.SEEK_END)
+	if err != nil {
+		logrus.Debugf("error seeking to end of download file: %v", err)
+		offset = 0
+
+		ld.releaseDownloadFile(ld.tmpFile, ld.staged, false)
+		ld.staged = false
+		ld.verifier = nil
+		ld.tmpFile, err = createDownloadFile()
+		if err != nil {
+			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
+	} else if offset != 0 {
+		logrus.Debugf("attempting to resume download of %q from %d bytes", ld.digest, offset)
 	}
 
 	tmpFile := ld.tmpFile
@@ -224,7 +231,7 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		}
 	}
 
-	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, layerDownload), progressOutput, size-offset, ld.ID(), "Downloading")
+	reader := progress.NewProgressReader(ratelimit.NewReader(ctx, ioutils.NewCancelReadCloser(ctx, layerDownload), ld.limiters...), progressOutput, size-offset, ld.ID(), throttledAction("Downloading", ld.limiters))
 	defer reader.Close()
 
 	if ld.verifier == nil {
@@ -232,6 +239,14 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 		if err != nil {
 			return nil, 0, xfer.DoNotRetry{Err: err}
 		}
+		if offset != 0 {
+			// The partial download was left by an earlier pull, so
+			// what it holds must be verified with the rest of the
+			// blob.
+			if err := ld.verifyDownloadFile(offset); err != nil {
+				return nil, 0, xfer.DoNotRetry{Err: err}
+			}
+		}
 	}
 
 	_, err = io.Copy(tmpFile, io.TeeReader(reader, ld.verifier))
@@ -260,6 +275,11 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 			return nil, 0, err
 		}
+
+		// Don't keep a corrupted blob in the staging store.
+		if err := ld.truncateDownloadFile(); err != nil {
+			logrus.Errorf("Failed to truncate download file: %v", err)
+		}
 		return nil, 0, xfer.DoNotRetry{Err: err}
 	}
 
@@ -267,12 +287,10 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 
 	logrus.Debugf("Downloaded %s to tempfile %s", ld.ID(), tmpFile.Name())
 
+	staged := ld.staged
 	_, err = tmpFile.Seek(0, os.SEEK_SET)
 	if err != nil {
-		tmpFile.Close()
-		if err := os.Remove(tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
+		ld.releaseDownloadFile(tmpFile, staged, false)
 		ld.tmpFile = nil
 		ld.verifier = nil
 		return nil, 0, xfer.DoNotRetry{Err: err}
@@ -283,22 +301,67 @@ func (ld *v2LayerDescriptor) Download(ctx context.Context, progressOutput progre
 	ld.tmpFile = nil
 
 	return ioutils.NewReadCloserWrapper(tmpFile, func() error {
-		tmpFile.Close()
-		err := os.RemoveAll(tmpFile.Name())
-		if err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", tmpFile.Name())
-		}
-		return err
+		return ld.releaseDownloadFile(tmpFile, staged, false)
 	}), size, nil
 }
 
 func (ld *v2LayerDescriptor) Close() {
 	if ld.tmpFile != nil {
-		ld.tmpFile.Close()
-		if err := os.RemoveAll(ld.tmpFile.Name()); err != nil {
-			logrus.Errorf("Failed to remove temp file: %s", ld.tmpFile.Name())
+		// Keep a partial download in the staging store, so that a
+		// later pull can resume it.
+		ld.releaseDownloadFile(ld.tmpFile, ld.staged, true)
+	}
+}
+
+// openDownloadFile opens the file the blob is downloaded to. If a staging
+// store is configured, the partial download of the blob it holds is opened.
+func (ld *v2LayerDescriptor) openDownloadFile() (*os.File, error) {
+	if ld.stagingStore != nil {
+		f, err := ld.stagingStore.Open(ld.digest)
+		if err == nil {
+			ld.staged = true
+			return f, nil
 		}
+		logrus.Debugf("not staging download of %s: %v", ld.digest, err)
+	}
+	ld.staged = false
+	return createDownloadFile()
+}
+
+// releaseDownloadFile closes a download file and removes it. A staged
+// download is kept in the staging store instead if keep is set and it isn't
+// empty.
+func (ld *v2LayerDescriptor) releaseDownloadFile(f *os.File, staged, keep bool) error {
+	if staged {
+		fi, err := f.Stat()
+		keep = keep && err == nil && fi.Size() > 0
+		f.Close()
+		if err := ld.stagingStore.Release(ld.digest, keep); err != nil {
+			logrus.Errorf("Failed to release partial download of %s: %v", ld.digest, err)
+			return err
+		}
+		return nil
+	}
+
+	f.Close()
+	err := os.RemoveAll(f.Name())
+	if err != nil {
+		logrus.Errorf("Failed to remove temp file: %s", f.Name())
 	}
+	return err
+}
+
+// verifyDownloadFile feeds the first offset bytes of the download file to
+// the verifier, and seeks back to the end of the file.
+func (ld *v2LayerDescriptor) verifyDownloadFile(offset int64) error {
+	if _, err := ld.tmpFile.Seek(0, os.SEEK_SET); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(ld.verifier, ld.tmpFile, offset); err != nil {
+		return err
+	}
+	_, err := ld.tmpFile.Seek(offset, os.SEEK_SET)
+	return err
 }
 
 func (ld *v2LayerDescriptor) truncateDownloadFile() error {
@@ -370,7 +433,7 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named) (tagUpdat
 
 	switch v := manifest.(type) {
 	case *schema1.SignedManifest:
-		imageID, manifestDigest, err = p.pullSchema1(ctx, ref, v)
+		imageID, manifestDigest, err = p.pullSchema1(ctx, ref, v, nil)
 		if err != nil {
 			return false, err
 		}
@@ -410,7 +473,10 @@ func (p *v2Puller) pullV2Tag(ctx context.Context, ref reference.Named) (tagUpdat
 	return true, nil
 }
 
-func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverifiedManifest *schema1.SignedManifest) (imageID image.ID, manifestDigest digest.Digest, err error) {
+// pullSchema1 pulls a schema1 manifest. If the manifest was selected from a
+// manifest list, platform is the platform of its entry, which is recorded in
+// the image config since schema1 manifests don't reliably carry it.
+func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverifiedManifest *schema1.SignedManifest, platform *manifestlist.PlatformSpec) (imageID image.ID, manifestDigest digest.Digest, err error) {
 	var verifiedManifest *schema1.Manifest
 	verifiedManifest, err = verifySchema1Manifest(unverifiedManifest, ref)
 	if err != nil {
@@ -429,6 +495,14 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 		return "", "", err
 	}
 
+	// A manifest selected from a manifest list already matches the
+	// requested platform.
+	if platform == nil && p.config.Platform != nil {
+		if err := checkSchema1Platform(verifiedManifest, *p.config.Platform); err != nil {
+			return "", "", err
+		}
+	}
+
 	var descriptors []xfer.DownloadDescriptor
 
 	// Image history converted to the new format
@@ -461,6 +535,8 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 			repoInfo:          p.repoInfo,
 			repo:              p.repo,
 			V2MetadataService: p.V2MetadataService,
+			stagingStore:      p.config.StagingStore,
+			limiters:          p.config.limiters(),
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -477,6 +553,12 @@ func (p *v2Puller) pullSchema1(ctx context.Context, ref reference.Named, unverif
 		return "", "", err
 	}
 
+	if platform != nil {
+		if config, err = setConfigPlatform(config, *platform); err != nil {
+			return "", "", err
+		}
+	}
+
 	imageID, err = p.config.ImageStore.Create(config)
 	if err != nil {
 		return "", "", err
@@ -495,9 +577,14 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 
 	target := mfst.Target()
 	imageID = image.ID(target.Digest)
-	if _, err := p.config.ImageStore.Get(imageID); err == nil {
+	if img, err := p.config.ImageStore.Get(imageID); err == nil {
 		// If the image already exists locally, no need to pull
 		// anything.
+		if p.config.Platform != nil {
+			if err := checkConfigPlatform(*img, *p.config.Platform); err != nil {
+				return "", "", err
+			}
+		}
 		return imageID, manifestDigest, nil
 	}
 
@@ -527,6 +614,8 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 			repo:              p.repo,
 			repoInfo:          p.repoInfo,
 			V2MetadataService: p.V2MetadataService,
+			stagingStore:      p.config.StagingStore,
+			limiters:          p.config.limiters(),
 		}
 
 		descriptors = append(descriptors, layerDescriptor)
@@ -537,11 +626,22 @@ func (p *v2Puller) pullSchema2(ctx context.Context, ref reference.Named, mfst *s
 		unmarshalledConfig image.Image  // deserialized image config
 		downloadRootFS     image.RootFS // rootFS to use for registering layers.
 	)
-	if runtime.GOOS == "windows" {
+	// When a platform is requested, the config is received before the
+	// layers are downloaded, so that an image for another platform is
+	// rejected without downloading it.
+	if runtime.GOOS == "windows" || p.config.Platform != nil {
 		configJSON, unmarshalledConfig, err = receiveConfig(configChan, errChan)
 		if err != nil {
 			return "", "", err
 		}
+		if p.config.Platform != nil {
+			if err := checkConfigPlatform(unmarshalledConfig, *p.config.Platform); err != nil {
+				cancel()
+				return "", "", err
+			}
+		}
+	}
+	if runtime.GOOS == "windows" {
 		if unmarshalledConfig.RootFS == nil {
 			return "", "", errors.New("image config has no rootfs section")
 		}
@@ -622,20 +722,16 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 		return "", "", err
 	}
 
-	var manifestDigest digest.Digest
-	for _, manifestDescriptor := range mfstList.Manifests {
-		// TODO(aaronl): The manifest list spec supports optional
-		// "features" and "variant" fields. These are not yet used.
-		// Once they are, their values should be interpreted here.
-		if manifestDescriptor.Platform.Architecture == runtime.GOARCH && manifestDescriptor.Platform.OS == runtime.GOOS {
-			manifestDigest = manifestDescriptor.Digest
-			break
-		}
+	platform := manifestlist.PlatformSpec{OS: runtime.GOOS, Architecture: runtime.GOARCH}
+	if p.config.Platform != nil {
+		platform = *p.config.Platform
 	}
 
-	if manifestDigest == "" {
-		return "", "", errors.New("no supported platform found in manifest list")
+	manifestDescriptor, err := selectManifest(mfstList.Manifests, platform)
+	if err != nil {
+		return "", "", err
 	}
+	manifestDigest := manifestDescriptor.Digest
 
 	manSvc, err := p.repo.Manifests(ctx)
 	if err != nil {
@@ -654,7 +750,7 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 
 	switch v := manifest.(type) {
 	case *schema1.SignedManifest:
-		imageID, _, err = p.pullSchema1(ctx, manifestRef, v)
+		imageID, _, err = p.pullSchema1(ctx, manifestRef, v, &manifestDescriptor.Platform)
 		if err != nil {
 			return "", "", err
 		}
@@ -670,6 +766,78 @@ func (p *v2Puller) pullManifestList(ctx context.Context, ref reference.Named, mf
 	return imageID, manifestListDigest, err
 }
 
+// selectManifest returns the entry of a manifest list matching platform. The
+// variant of an entry is only compared if platform has one.
+func selectManifest(manifests []manifestlist.ManifestDescriptor, platform manifestlist.PlatformSpec) (manifestlist.ManifestDescriptor, error) {
+	// TODO(aaronl): The manifest list spec supports optional
+	// "features" fields. These are not yet used. Once they are,
+	// their values should be interpreted here.
+	for _, manifestDescriptor := range manifests {
+		if manifestDescriptor.Platform.Architecture != platform.Architecture || manifestDescriptor.Platform.OS != platform.OS {
+			continue
+		}
+		if platform.Variant != "" && manifestDescriptor.Platform.Variant != platform.Variant {
+			continue
+		}
+		return manifestDescriptor, nil
+	}
+	return manifestlist.ManifestDescriptor{}, fmt.Errorf("no matching manifest for platform %s in manifest list", formatPlatform(platform))
+}
+
+// checkConfigPlatform returns an error if the platform declared by an image
+// config doesn't match the requested platform. Fields the config doesn't set
+// are not compared.
+func checkConfigPlatform(img image.Image, platform manifestlist.PlatformSpec) error {
+	if (img.OS != "" && img.OS != platform.OS) ||
+		(img.Architecture != "" && img.Architecture != platform.Architecture) ||
+		(img.Variant != "" && platform.Variant != "" && img.Variant != platform.Variant) {
+		actual := manifestlist.PlatformSpec{OS: img.OS, Architecture: img.Architecture, Variant: img.Variant}
+		return fmt.Errorf("image is for platform %s, not the requested platform %s", formatPlatform(actual), formatPlatform(platform))
+	}
+	return nil
+}
+
+// checkSchema1Platform returns an error if the platform declared by a
+// schema1 manifest doesn't match the requested platform. The platform is
+// read from the image config of the top-most layer, and the architecture
+// falls back to the one of the manifest.
+func checkSchema1Platform(m *schema1.Manifest, platform manifestlist.PlatformSpec) error {
+	var img image.Image
+	if len(m.History) > 0 {
+		if err := json.Unmarshal([]byte(m.History[0].V1Compatibility), &img); err != nil {
+			return err
+		}
+	}
+	if img.Architecture == "" {
+		img.Architecture = m.Architecture
+	}
+	return checkConfigPlatform(img, platform)
+}
+
+// setConfigPlatform records platform in the serialized image config.
+func setConfigPlatform(config []byte, platform manifestlist.PlatformSpec) ([]byte, error) {
+	var c map[string]*json.RawMessage
+	if err := json.Unmarshal(config, &c); err != nil {
+		return nil, err
+	}
+	c["os"] = rawJSON(platform.OS)
+	c["architecture"] = rawJSON(platform.Architecture)
+	if platform.Variant != "" {
+		c["variant"] = rawJSON(platform.Variant)
+	} else {
+		delete(c, "variant")
+	}
+	return json.Marshal(c)
+}
+
+func rawJSON(value interface{}) *json.RawMessage {
+	jsonval, err := json.Marshal(value)
+	if err != nil {
+		return nil
+	}
+	return (*json.RawMessage)(&jsonval)
+}
+
 func (p *v2Puller) pullSchema2ImageConfig(ctx context.Context, dgst digest.Digest) (configJSON []byte, err error) {
 	blobs := p.repo.Blobs(ctx)
 	configJSON, err = blobs.Get(ctx, dgst)
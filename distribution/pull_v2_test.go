@@ -2,6 +2,7 @@ package distribution
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"reflect"
 	"runtime"
@@ -9,7 +10,9 @@ import (
 	"testing"
 
 	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
 	"github.com/docker/distribution/manifest/schema1"
+	"github.com/docker/docker/image"
 	"github.com/docker/docker/reference"
 )
 
@@ -181,3 +184,122 @@ func TestValidateManifest(t *testing.T) {
 		t.Fatal("expected validateManifest to fail with digest error")
 	}
 }
+
+func TestParsePlatform(t *testing.T) {
+	valids := map[string]manifestlist.PlatformSpec{
+		"linux/amd64":   {OS: "linux", Architecture: "amd64"},
+		"linux/arm64":   {OS: "linux", Architecture: "arm64"},
+		"linux/arm/v7":  {OS: "linux", Architecture: "arm", Variant: "v7"},
+		"Windows/AMD64": {OS: "windows", Architecture: "amd64"},
+		"linux/ARM/V6":  {OS: "linux", Architecture: "arm", Variant: "v6"},
+		"freebsd/386":   {OS: "freebsd", Architecture: "386"},
+	}
+	for value, expected := range valids {
+		platform, err := ParsePlatform(value)
+		if err != nil {
+			t.Fatalf("Expected %q to be valid, got error: %v", value, err)
+		}
+		if !reflect.DeepEqual(*platform, expected) {
+			t.Fatalf("Expected %q to be parsed as %+v, got %+v", value, expected, *platform)
+		}
+	}
+
+	for _, value := range []string{"", "linux", "/amd64", "linux/", "linux/amd64/", "linux/arm/v7/extra"} {
+		if _, err := ParsePlatform(value); err == nil {
+			t.Fatalf("Expected %q to be invalid", value)
+		}
+	}
+}
+
+func TestSelectManifest(t *testing.T) {
+	manifests := []manifestlist.ManifestDescriptor{
+		{Platform: manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"}},
+		{Platform: manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v6"}},
+		{Platform: manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v7"}},
+		{Platform: manifestlist.PlatformSpec{OS: "windows", Architecture: "amd64"}},
+	}
+	for i := range manifests {
+		manifests[i].Digest = digest.Digest(fmt.Sprintf("sha256:%064d", i))
+	}
+
+	tests := []struct {
+		platform manifestlist.PlatformSpec
+		expected int
+	}{
+		{manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"}, 0},
+		{manifestlist.PlatformSpec{OS: "windows", Architecture: "amd64"}, 3},
+		{manifestlist.PlatformSpec{OS: "linux", Architecture: "arm"}, 1},
+		{manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v7"}, 2},
+	}
+	for _, test := range tests {
+		descriptor, err := selectManifest(manifests, test.platform)
+		if err != nil {
+			t.Fatalf("Expected a manifest for %+v, got error: %v", test.platform, err)
+		}
+		if descriptor.Digest != manifests[test.expected].Digest {
+			t.Fatalf("Expected manifest %d for %+v, got %s", test.expected, test.platform, descriptor.Digest)
+		}
+	}
+
+	for _, platform := range []manifestlist.PlatformSpec{
+		{OS: "linux", Architecture: "arm64"},
+		{OS: "linux", Architecture: "arm", Variant: "v8"},
+	} {
+		if _, err := selectManifest(manifests, platform); err == nil || !strings.Contains(err.Error(), formatPlatform(platform)) {
+			t.Fatalf("Expected an error naming %s, got %v", formatPlatform(platform), err)
+		}
+	}
+}
+
+func TestCheckConfigPlatform(t *testing.T) {
+	img := image.Image{V1Image: image.V1Image{OS: "linux", Architecture: "arm"}, Variant: "v7"}
+	if err := checkConfigPlatform(img, manifestlist.PlatformSpec{OS: "linux", Architecture: "arm"}); err != nil {
+		t.Fatalf("Expected the config to match, got error: %v", err)
+	}
+	if err := checkConfigPlatform(img, manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v7"}); err != nil {
+		t.Fatalf("Expected the config to match, got error: %v", err)
+	}
+	if err := checkConfigPlatform(img, manifestlist.PlatformSpec{OS: "linux", Architecture: "arm", Variant: "v6"}); err == nil {
+		t.Fatal("Expected a different variant not to match")
+	}
+	if err := checkConfigPlatform(img, manifestlist.PlatformSpec{OS: "linux", Architecture: "arm64"}); err == nil {
+		t.Fatal("Expected a different architecture not to match")
+	}
+}
+
+func TestCheckSchema1Platform(t *testing.T) {
+	m := &schema1.Manifest{
+		Architecture: "amd64",
+		History: []schema1.History{
+			{V1Compatibility: `{"id":"a","os":"linux"}`},
+		},
+	}
+	if err := checkSchema1Platform(m, manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"}); err != nil {
+		t.Fatalf("Expected the manifest to match, got error: %v", err)
+	}
+	if err := checkSchema1Platform(m, manifestlist.PlatformSpec{OS: "linux", Architecture: "arm64"}); err == nil {
+		t.Fatal("Expected the architecture of the manifest not to match")
+	}
+
+	m.History[0].V1Compatibility = `{"id":"a","os":"windows","architecture":"amd64"}`
+	if err := checkSchema1Platform(m, manifestlist.PlatformSpec{OS: "linux", Architecture: "amd64"}); err == nil {
+		t.Fatal("Expected a different OS not to match")
+	}
+}
+
+func TestSetConfigPlatform(t *testing.T) {
+	config, err := setConfigPlatform([]byte(`{"os":"linux","architecture":"amd64","variant":"v1","rootfs":{"type":"layers"}}`), manifestlist.PlatformSpec{OS: "linux", Architecture: "arm64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var img image.Image
+	if err := json.Unmarshal(config, &img); err != nil {
+		t.Fatal(err)
+	}
+	if img.OS != "linux" || img.Architecture != "arm64" || img.Variant != "" {
+		t.Fatalf("Expected platform linux/arm64, got %s/%s/%s", img.OS, img.Architecture, img.Variant)
+	}
+	if img.RootFS == nil || img.RootFS.Type != "layers" {
+		t.Fatalf("Expected the rest of the config to be preserved, got %s", config)
+	}
+}
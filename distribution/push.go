@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/engine-api/types"
@@ -48,6 +49,19 @@ type ImagePushConfig struct {
 	TrustKey libtrust.PrivateKey
 	// UploadManager dispatches uploads.
 	UploadManager *xfer.LayerUploadManager
+	// BandwidthLimiter limits the bandwidth of the push, on top of the
+	// limit of UploadManager. If nil, only the limit of UploadManager
+	// applies.
+	BandwidthLimiter *ratelimit.Limiter
+}
+
+// limiters returns the limiters of the blob readers of the push.
+func (config *ImagePushConfig) limiters() []*ratelimit.Limiter {
+	limiters := []*ratelimit.Limiter{config.BandwidthLimiter}
+	if config.UploadManager != nil {
+		limiters = append(limiters, config.UploadManager.BandwidthLimiter())
+	}
+	return limiters
 }
 
 // Pusher is an interface that abstracts pushing for different API versions.
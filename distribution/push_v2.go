@@ -19,6 +19,7 @@ import (
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/pkg/stringid"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
@@ -139,6 +140,7 @@ func (p *v2Pusher) pushV2Tag(ctx context.Context, ref reference.NamedTagged, ima
 		repoInfo:          p.repoInfo,
 		repo:              p.repo,
 		pushState:         &p.pushState,
+		limiters:          p.config.limiters(),
 	}
 
 	// Loop bounds condition is to avoid pushing the base layer on Windows.
@@ -224,6 +226,7 @@ type v2PushDescriptor struct {
 	repoInfo          reference.Named
 	repo              distribution.Repository
 	pushState         *pushState
+	limiters          []*ratelimit.Limiter
 	remoteDescriptor  distribution.Descriptor
 }
 
@@ -359,15 +362,17 @@ func (pd *v2PushDescriptor) Upload(ctx context.Context, progressOutput progress.
 	// don't care if this fails; best effort
 	size, _ := pd.layer.DiffSize()
 
-	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, arch), progressOutput, size, pd.ID(), "Pushing")
+	reader := progress.NewProgressReader(ioutils.NewCancelReadCloser(ctx, arch), progressOutput, size, pd.ID(), throttledAction("Pushing", pd.limiters))
 	compressedReader, compressionDone := compress(reader)
 	defer func() {
 		reader.Close()
 		<-compressionDone
 	}()
 
+	// The bandwidth is limited after compression, since the compressed
+	// layer is what is sent to the registry.
 	digester := digest.Canonical.New()
-	tee := io.TeeReader(compressedReader, digester.Hash())
+	tee := io.TeeReader(ratelimit.NewReader(ctx, compressedReader, pd.limiters...), digester.Hash())
 
 	nn, err := layerUpload.ReadFrom(tee)
 	compressedReader.Close()
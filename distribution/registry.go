@@ -13,8 +13,10 @@ import (
 	"github.com/docker/distribution/registry/client/auth"
 	"github.com/docker/distribution/registry/client/transport"
 	"github.com/docker/docker/dockerversion"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/registry"
 	"github.com/docker/engine-api/types"
+	"github.com/docker/go-units"
 	"golang.org/x/net/context"
 )
 
@@ -128,3 +130,12 @@ func (th *existingTokenHandler) AuthorizeRequest(req *http.Request, params map[s
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", th.token))
 	return nil
 }
+
+// throttledAction returns the progress action of a blob transfer, along with
+// the bandwidth it is throttled to by limiters, if any.
+func throttledAction(action string, limiters []*ratelimit.Limiter) string {
+	if limit := ratelimit.MinLimit(limiters...); limit != 0 {
+		return fmt.Sprintf("%s (throttled to %s/s)", action, units.HumanSize(float64(limit)))
+	}
+	return action
+}
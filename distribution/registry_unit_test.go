@@ -9,6 +9,7 @@ import (
 	"testing"
 
 	"github.com/Sirupsen/logrus"
+	"github.com/docker/docker/pkg/ratelimit"
 	"github.com/docker/docker/reference"
 	"github.com/docker/docker/registry"
 	"github.com/docker/docker/utils"
@@ -131,3 +132,18 @@ func TestTokenPassThruDifferentHost(t *testing.T) {
 		t.Fatal("Redirect should not forward Authorization header to another host")
 	}
 }
+
+func TestThrottledAction(t *testing.T) {
+	if action := throttledAction("Downloading", nil); action != "Downloading" {
+		t.Fatalf("Expected an action without limiters to be unchanged, got %q", action)
+	}
+	if action := throttledAction("Downloading", []*ratelimit.Limiter{nil, ratelimit.NewLimiter(0)}); action != "Downloading" {
+		t.Fatalf("Expected an action without limits to be unchanged, got %q", action)
+	}
+
+	limiters := []*ratelimit.Limiter{ratelimit.NewLimiter(10000000), nil, ratelimit.NewLimiter(2000000)}
+	expected := "Pushing (throttled to 2 MB/s)"
+	if action := throttledAction("Pushing", limiters); action != expected {
+		t.Fatalf("Expected %q, got %q", expected, action)
+	}
+}
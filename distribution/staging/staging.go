@@ -0,0 +1,129 @@
+// Package staging keeps partially downloaded blobs between pulls, so that a
+// download interrupted by a client disconnecting or a daemon restart can be
+// resumed by a later pull of the same blob.
+package staging
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/Sirupsen/logrus"
+	"github.com/docker/distribution/digest"
+)
+
+// ErrInUse is returned by Open if the blob is already being downloaded.
+var ErrInUse = errors.New("blob is already being downloaded")
+
+// gcInterval is the minimum interval between two garbage collections of
+// the staging area.
+const gcInterval = time.Hour
+
+// Store is a content-addressed staging area for partially downloaded blobs.
+// Partial downloads which aren't written to for the TTL of the store are
+// garbage-collected. Store is goroutine-safe.
+type Store struct {
+	sync.Mutex
+	root   string
+	ttl    time.Duration
+	inUse  map[digest.Digest]struct{}
+	lastGC time.Time
+}
+
+// NewStore creates a staging area in root, and removes the partial downloads
+// left in it which are older than ttl.
+func NewStore(root string, ttl time.Duration) (*Store, error) {
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, err
+	}
+	s := &Store{
+		root:  root,
+		ttl:   ttl,
+		inUse: make(map[digest.Digest]struct{}),
+	}
+	s.GC()
+	return s, nil
+}
+
+func (s *Store) path(dgst digest.Digest) string {
+	return filepath.Join(s.root, string(dgst.Algorithm()), dgst.Hex())
+}
+
+// Open opens the partial download of dgst for reading and writing, creating
+// it if there is none. The download can't be opened again until it is
+// released with Release.
+func (s *Store) Open(dgst digest.Digest) (*os.File, error) {
+	if err := dgst.Validate(); err != nil {
+		return nil, err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	if _, inUse := s.inUse[dgst]; inUse {
+		return nil, ErrInUse
+	}
+	if time.Since(s.lastGC) > gcInterval {
+		s.gc()
+	}
+
+	path := s.path(dgst)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	s.inUse[dgst] = struct{}{}
+	return f, nil
+}
+
+// Release releases the partial download of dgst opened with Open. If keep is
+// true, the download is kept to be resumed by a later pull. Otherwise it is
+// removed. The caller must close the file returned by Open first.
+func (s *Store) Release(dgst digest.Digest, keep bool) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.inUse, dgst)
+	if keep {
+		return nil
+	}
+	if err := os.Remove(s.path(dgst)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// GC removes the partial downloads which weren't written to for the TTL of
+// the store.
+func (s *Store) GC() {
+	s.Lock()
+	defer s.Unlock()
+
+	s.gc()
+}
+
+func (s *Store) gc() {
+	s.lastGC = time.Now()
+
+	filepath.Walk(s.root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		dgst := digest.NewDigestFromHex(filepath.Base(filepath.Dir(path)), info.Name())
+		if _, inUse := s.inUse[dgst]; inUse {
+			return nil
+		}
+		if time.Since(info.ModTime()) > s.ttl {
+			logrus.Debugf("removing expired partial download %s", dgst)
+			if err := os.Remove(path); err != nil {
+				logrus.Errorf("Failed to remove partial download %s: %v", dgst, err)
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,153 @@
+package staging
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/docker/distribution/digest"
+)
+
+func TestOpenRelease(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "staging-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	s, err := NewStore(tmpDir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Open(digest.Digest("sha256:invalid")); err == nil {
+		t.Fatal("Expected an invalid digest to be rejected")
+	}
+
+	dgst := digest.FromBytes([]byte("layer"))
+	f, err := s.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Open(dgst); err != ErrInUse {
+		t.Fatalf("Expected ErrInUse, got %v", err)
+	}
+	if _, err := f.Write([]byte("lay")); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := s.Release(dgst, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// The partial download is kept for the next pull.
+	f, err = s.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := ioutil.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "lay" {
+		t.Fatalf("Expected the partial download to hold %q, got %q", "lay", data)
+	}
+	f.Close()
+	if err := s.Release(dgst, false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.path(dgst)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the partial download to be removed, got %v", err)
+	}
+}
+
+func TestGC(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "staging-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	s, err := NewStore(tmpDir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired := digest.FromBytes([]byte("expired"))
+	recent := digest.FromBytes([]byte("recent"))
+	inUse := digest.FromBytes([]byte("in use"))
+	for _, dgst := range []digest.Digest{expired, recent, inUse} {
+		f, err := s.Open(dgst)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+		if dgst != inUse {
+			if err := s.Release(dgst, true); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, dgst := range []digest.Digest{expired, inUse} {
+		if err := os.Chtimes(s.path(dgst), old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s.GC()
+
+	if _, err := os.Stat(s.path(expired)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the expired partial download to be removed, got %v", err)
+	}
+	for _, dgst := range []digest.Digest{recent, inUse} {
+		if _, err := os.Stat(s.path(dgst)); err != nil {
+			t.Fatalf("Expected partial download %s to be kept, got %v", dgst, err)
+		}
+	}
+}
+
+// TestNewStoreRemovesExpired checks that the partial downloads which expired
+// while the daemon was stopped are removed when it starts.
+func TestNewStoreRemovesExpired(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "staging-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	s, err := NewStore(tmpDir, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dgst := digest.FromBytes([]byte("expired"))
+	f, err := s.Open(dgst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if err := s.Release(dgst, true); err != nil {
+		t.Fatal(err)
+	}
+
+	// A store with a longer TTL keeps the download.
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(s.path(dgst), old, old); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewStore(tmpDir, 3*time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.path(dgst)); err != nil {
+		t.Fatalf("Expected the partial download to be kept, got %v", err)
+	}
+
+	if _, err := NewStore(tmpDir, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.path(dgst)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the expired partial download to be removed, got %v", err)
+	}
+}
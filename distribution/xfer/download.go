@@ -12,6 +12,7 @@ import (
 	"github.com/docker/docker/pkg/archive"
 	"github.com/docker/docker/pkg/ioutils"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"golang.org/x/net/context"
 )
 
@@ -23,6 +24,7 @@ const maxDownloadAttempts = 5
 type LayerDownloadManager struct {
 	layerStore layer.Store
 	tm         TransferManager
+	limiter    *ratelimit.Limiter
 }
 
 // NewLayerDownloadManager returns a new LayerDownloadManager.
@@ -30,9 +32,23 @@ func NewLayerDownloadManager(layerStore layer.Store, concurrencyLimit int) *Laye
 	return &LayerDownloadManager{
 		layerStore: layerStore,
 		tm:         NewTransferManager(concurrencyLimit),
+		limiter:    ratelimit.NewLimiter(0),
 	}
 }
 
+// SetBandwidthLimit limits the total bandwidth of the downloads to
+// bytesPerSec bytes per second. If bytesPerSec is 0, the bandwidth is not
+// limited.
+func (ldm *LayerDownloadManager) SetBandwidthLimit(bytesPerSec int64) {
+	ldm.limiter.SetLimit(bytesPerSec)
+}
+
+// BandwidthLimiter returns the limiter shared by the blob readers of all
+// downloads.
+func (ldm *LayerDownloadManager) BandwidthLimiter() *ratelimit.Limiter {
+	return ldm.limiter
+}
+
 type downloadTransfer struct {
 	Transfer
 
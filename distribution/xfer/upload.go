@@ -8,6 +8,7 @@ import (
 	"github.com/docker/distribution"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/ratelimit"
 	"golang.org/x/net/context"
 )
 
@@ -16,16 +17,31 @@ const maxUploadAttempts = 5
 // LayerUploadManager provides task management and progress reporting for
 // uploads.
 type LayerUploadManager struct {
-	tm TransferManager
+	tm      TransferManager
+	limiter *ratelimit.Limiter
 }
 
 // NewLayerUploadManager returns a new LayerUploadManager.
 func NewLayerUploadManager(concurrencyLimit int) *LayerUploadManager {
 	return &LayerUploadManager{
-		tm: NewTransferManager(concurrencyLimit),
+		tm:      NewTransferManager(concurrencyLimit),
+		limiter: ratelimit.NewLimiter(0),
 	}
 }
 
+// SetBandwidthLimit limits the total bandwidth of the uploads to
+// bytesPerSec bytes per second. If bytesPerSec is 0, the bandwidth is not
+// limited.
+func (lum *LayerUploadManager) SetBandwidthLimit(bytesPerSec int64) {
+	lum.limiter.SetLimit(bytesPerSec)
+}
+
+// BandwidthLimiter returns the limiter shared by the blob readers of all
+// uploads.
+func (lum *LayerUploadManager) BandwidthLimiter() *ratelimit.Limiter {
+	return lum.limiter
+}
+
 type uploadTransfer struct {
 	Transfer
 
@@ -294,3 +294,33 @@ func TestLoadDaemonConfigWithRegistryOptions(t *testing.T) {
 		t.Fatal("expected disable-legacy-registry to be true, got false")
 	}
 }
+
+func TestLoadDaemonConfigWithRegistryMirrorsFor(t *testing.T) {
+	c := &daemon.Config{}
+	common := &cli.CommonFlags{}
+	flags := mflag.NewFlagSet("test", mflag.ContinueOnError)
+	c.ServiceOptions.InstallCliFlags(flags, absentFromHelp)
+
+	f, err := ioutil.TempFile("", "docker-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	configFile := f.Name()
+	defer os.Remove(configFile)
+
+	f.Write([]byte(`{"registry-mirrors-for": {"registry.corp:5000": ["mirror1", "https://mirror2:5000"]}}`))
+	f.Close()
+
+	loadedConfig, err := loadDaemonCliConfig(c, flags, common, configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loadedConfig == nil {
+		t.Fatal("expected configuration, got nil")
+	}
+
+	m := loadedConfig.RegistryMirrors["registry.corp:5000"]
+	if len(m) != 2 || m[0] != "mirror1" || m[1] != "https://mirror2:5000" {
+		t.Fatalf("expected 2 mirrors of registry.corp:5000, got %v", m)
+	}
+}
@@ -53,6 +53,7 @@ type Image struct {
 	History    []History `json:"history,omitempty"`
 	OSVersion  string    `json:"os.version,omitempty"`
 	OSFeatures []string  `json:"os.features,omitempty"`
+	Variant    string    `json:"variant,omitempty"`
 
 	// rawJSON caches the immutable JSON associated with this image.
 	rawJSON []byte
@@ -120,7 +121,9 @@ type History struct {
 type Exporter interface {
 	Load(io.ReadCloser, io.Writer, bool) error
 	// TODO: Load(net.Context, io.ReadCloser, <- chan StatusMessage) error
-	Save([]string, io.Writer) error
+	// Save writes the named images to the writer, in the given format.
+	// The layers of the images or chain IDs in excludes are left out.
+	Save(names, excludes []string, format string, outStream io.Writer) error
 }
 
 // NewFromJSON creates an Image configuration from json.
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"os"
 	"sync"
 
 	"github.com/Sirupsen/logrus"
@@ -19,6 +20,8 @@ type Store interface {
 	Search(partialID string) (ID, error)
 	SetParent(id ID, parent ID) error
 	GetParent(id ID) (ID, error)
+	SetAnnotations(id ID, annotations map[string]string) error
+	GetAnnotations(id ID) (map[string]string, error)
 	Children(id ID) []ID
 	Map() map[ID]*Image
 	Heads() map[ID]*Image
@@ -249,6 +252,38 @@ func (is *store) GetParent(id ID) (ID, error) {
 	return ID(d), nil // todo: validate?
 }
 
+// SetAnnotations stores the annotations of an image, such as the annotations
+// of the OCI manifest it was loaded from.
+func (is *store) SetAnnotations(id ID, annotations map[string]string) error {
+	is.Lock()
+	defer is.Unlock()
+	if is.images[id] == nil {
+		return fmt.Errorf("unknown image ID %s", id.String())
+	}
+	data, err := json.Marshal(annotations)
+	if err != nil {
+		return err
+	}
+	return is.fs.SetMetadata(id, "annotations", data)
+}
+
+// GetAnnotations returns the annotations of an image. An image without
+// annotations has none.
+func (is *store) GetAnnotations(id ID) (map[string]string, error) {
+	data, err := is.fs.GetMetadata(id, "annotations")
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var annotations map[string]string
+	if err := json.Unmarshal(data, &annotations); err != nil {
+		return nil, err
+	}
+	return annotations, nil
+}
+
 func (is *store) Children(id ID) []ID {
 	is.Lock()
 	defer is.Unlock()
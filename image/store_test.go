@@ -3,6 +3,7 @@ package image
 import (
 	"io/ioutil"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/docker/distribution/digest"
@@ -298,3 +299,49 @@ func (ls *mockLayerGetReleaser) Get(layer.ChainID) (layer.Layer, error) {
 func (ls *mockLayerGetReleaser) Release(layer.Layer) ([]layer.Metadata, error) {
 	return nil, nil
 }
+
+func TestAnnotations(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "images-fs-store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+	fs, err := NewFSStoreBackend(tmpdir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	is, err := NewImageStore(fs, &mockLayerGetReleaser{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := is.Create([]byte(`{"comment": "abc1", "rootfs": {"type": "layers"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	annotations, err := is.GetAnnotations(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(annotations) != 0 {
+		t.Fatalf("expected no annotations, got %v", annotations)
+	}
+
+	expected := map[string]string{"org.opencontainers.image.version": "1.0"}
+	if err := is.SetAnnotations(id, expected); err != nil {
+		t.Fatal(err)
+	}
+	annotations, err = is.GetAnnotations(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(annotations, expected) {
+		t.Fatalf("expected annotations %v, got %v", expected, annotations)
+	}
+
+	if err := is.SetAnnotations(ID("sha256:0000000000000000000000000000000000000000000000000000000000000000"), expected); err == nil {
+		t.Fatal("expected setting the annotations of an unknown image to fail")
+	}
+}
@@ -41,6 +41,16 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	if err := chrootarchive.Untar(inTar, tmpDir, nil); err != nil {
 		return err
 	}
+
+	// load in OCI mode if there is an OCI image layout
+	layoutPath, err := safePath(tmpDir, ociLayoutFileName)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(layoutPath); err == nil {
+		return l.ociLoad(tmpDir, outStream, progressOutput)
+	}
+
 	// read manifest, if no file then load in legacy mode
 	manifestPath, err := safePath(tmpDir, manifestFileName)
 	if err != nil {
@@ -71,40 +81,16 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 		if err != nil {
 			return err
 		}
-		img, err := image.NewFromJSON(config)
-		if err != nil {
-			return err
-		}
-		var rootFS image.RootFS
-		rootFS = *img.RootFS
-		rootFS.DiffIDs = nil
-
-		if expected, actual := len(m.Layers), len(img.RootFS.DiffIDs); expected != actual {
-			return fmt.Errorf("invalid manifest, layers length mismatch: expected %q, got %q", expected, actual)
-		}
-
-		for i, diffID := range img.RootFS.DiffIDs {
-			layerPath, err := safePath(tmpDir, m.Layers[i])
+		var layerPaths []string
+		for _, layerFile := range m.Layers {
+			layerPath, err := safePath(tmpDir, layerFile)
 			if err != nil {
 				return err
 			}
-			r := rootFS
-			r.Append(diffID)
-			newLayer, err := l.ls.Get(r.ChainID())
-			if err != nil {
-				newLayer, err = l.loadLayer(layerPath, rootFS, diffID.String(), progressOutput)
-				if err != nil {
-					return err
-				}
-			}
-			defer layer.ReleaseAndLog(l.ls, newLayer)
-			if expected, actual := diffID, newLayer.DiffID(); expected != actual {
-				return fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
-			}
-			rootFS.Append(diffID)
+			layerPaths = append(layerPaths, layerPath)
 		}
 
-		imgID, err := l.is.Create(config)
+		imgID, err := l.loadImage(config, layerPaths, progressOutput)
 		if err != nil {
 			return err
 		}
@@ -118,7 +104,9 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 			if !ok {
 				return fmt.Errorf("invalid tag %q", repoTag)
 			}
-			l.setLoadedTag(ref, imgID, outStream)
+			if err := l.setLoadedTag(ref, imgID, outStream); err != nil {
+				return err
+			}
 		}
 
 		parentLinks = append(parentLinks, parentLink{imgID, m.Parent})
@@ -135,6 +123,66 @@ func (l *tarexporter) Load(inTar io.ReadCloser, outStream io.Writer, quiet bool)
 	return nil
 }
 
+// loadImage registers the layers of the image configuration config, which
+// are read from layerPaths unless they are already in the layer store, and
+// creates the image.
+func (l *tarexporter) loadImage(config []byte, layerPaths []string, progressOutput progress.Output) (image.ID, error) {
+	img, err := image.NewFromJSON(config)
+	if err != nil {
+		return "", err
+	}
+	var rootFS image.RootFS
+	rootFS = *img.RootFS
+	rootFS.DiffIDs = nil
+
+	if expected, actual := len(layerPaths), len(img.RootFS.DiffIDs); expected != actual {
+		return "", fmt.Errorf("invalid manifest, layers length mismatch: expected %d, got %d", expected, actual)
+	}
+
+	if err := l.checkExcludedLayers(*img.RootFS, layerPaths); err != nil {
+		return "", err
+	}
+
+	for i, diffID := range img.RootFS.DiffIDs {
+		r := rootFS
+		r.Append(diffID)
+		newLayer, err := l.ls.Get(r.ChainID())
+		if err != nil {
+			newLayer, err = l.loadLayer(layerPaths[i], rootFS, diffID.String(), progressOutput)
+			if err != nil {
+				return "", err
+			}
+		}
+		defer layer.ReleaseAndLog(l.ls, newLayer)
+		if expected, actual := diffID, newLayer.DiffID(); expected != actual {
+			return "", fmt.Errorf("invalid diffID for layer %d: expected %q, got %q", i, expected, actual)
+		}
+		rootFS.Append(diffID)
+	}
+
+	return l.is.Create(config)
+}
+
+// checkExcludedLayers verifies that the layers of rootFS which aren't in the
+// archive, because they were excluded when the image was saved, are already
+// in the layer store, before any layer of the image is registered.
+func (l *tarexporter) checkExcludedLayers(rootFS image.RootFS, layerPaths []string) error {
+	diffIDs := rootFS.DiffIDs
+	for i, diffID := range diffIDs {
+		if _, err := os.Stat(layerPaths[i]); !os.IsNotExist(err) {
+			continue
+		}
+		rootFS.DiffIDs = diffIDs[:i+1]
+		chainID := rootFS.ChainID()
+		existing, err := l.ls.Get(chainID)
+		if err != nil {
+			return fmt.Errorf("layer %s (chain ID %s) was excluded from the archive and is not present: load the image it was excluded with first", diffID, chainID)
+		}
+		layer.ReleaseAndLog(l.ls, existing)
+	}
+	return nil
+}
+
 func (l *tarexporter) setParentID(id, parentID image.ID) error {
 	img, err := l.is.Get(id)
 	if err != nil {
@@ -239,7 +287,9 @@ func (l *tarexporter) legacyLoad(tmpDir string, outStream io.Writer, progressOut
 			if err != nil {
 				return err
 			}
-			l.setLoadedTag(ref, imgID, outStream)
+			if err := l.setLoadedTag(ref, imgID, outStream); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -0,0 +1,449 @@
+package tarexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/docker/distribution/digest"
+	"github.com/docker/distribution/manifest/manifestlist"
+	"github.com/docker/distribution/manifest/schema2"
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+	"github.com/docker/docker/pkg/archive"
+	"github.com/docker/docker/pkg/progress"
+	"github.com/docker/docker/pkg/system"
+	"github.com/docker/docker/reference"
+)
+
+const (
+	ociLayoutFileName     = "oci-layout"
+	ociIndexFileName      = "index.json"
+	ociBlobsDirName       = "blobs"
+	ociImageLayoutVersion = "1.0.0"
+
+	mediaTypeOCIIndex    = "application/vnd.oci.image.index.v1+json"
+	mediaTypeOCIManifest = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeOCIConfig   = "application/vnd.oci.image.config.v1+json"
+	mediaTypeOCILayer    = "application/vnd.oci.image.layer.v1.tar"
+
+	// ociRefNameAnnotation is the annotation of an index entry holding the
+	// tag of the image.
+	ociRefNameAnnotation = "org.opencontainers.image.ref.name"
+	// ociImageNameAnnotation is the annotation of an index entry holding
+	// the full reference of the image, as set by containerd.
+	ociImageNameAnnotation = "io.containerd.image.name"
+)
+
+type ociLayout struct {
+	ImageLayoutVersion string `json:"imageLayoutVersion"`
+}
+
+type ociPlatform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+	Variant      string `json:"variant,omitempty"`
+}
+
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      digest.Digest     `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+	Platform    *ociPlatform      `json:"platform,omitempty"`
+}
+
+type ociIndex struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	Manifests     []ociDescriptor   `json:"manifests"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+type ociManifest struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	MediaType     string            `json:"mediaType,omitempty"`
+	Config        ociDescriptor     `json:"config"`
+	Layers        []ociDescriptor   `json:"layers"`
+	Annotations   map[string]string `json:"annotations,omitempty"`
+}
+
+// saveOCI writes the images of the session as an OCI image layout. The
+// configuration and layers keep their digests, and each tag of an image is
+// an entry of the index.
+func (s *saveSession) saveOCI(outStream io.Writer) error {
+	s.savedOCILayers = make(map[layer.ChainID]ociDescriptor)
+
+	tempDir, err := ioutil.TempDir("", "docker-export-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tempDir)
+
+	s.outDir = tempDir
+
+	index := ociIndex{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIIndex,
+		Manifests:     []ociDescriptor{},
+	}
+
+	for id, imageDescr := range s.images {
+		desc, err := s.saveOCIImage(id)
+		if err != nil {
+			return err
+		}
+		if len(imageDescr.refs) == 0 {
+			index.Manifests = append(index.Manifests, desc)
+			continue
+		}
+		for _, ref := range imageDescr.refs {
+			refDesc := desc
+			refDesc.Annotations = map[string]string{
+				ociRefNameAnnotation:   ref.Tag(),
+				ociImageNameAnnotation: ref.String(),
+			}
+			index.Manifests = append(index.Manifests, refDesc)
+		}
+	}
+
+	if err := writeJSONFile(filepath.Join(tempDir, ociLayoutFileName), ociLayout{ImageLayoutVersion: ociImageLayoutVersion}); err != nil {
+		return err
+	}
+	if err := writeJSONFile(filepath.Join(tempDir, ociIndexFileName), index); err != nil {
+		return err
+	}
+
+	fs, err := archive.Tar(tempDir, archive.Uncompressed)
+	if err != nil {
+		return err
+	}
+	defer fs.Close()
+
+	_, err = io.Copy(outStream, fs)
+	return err
+}
+
+// saveOCIImage writes the configuration, layers and manifest of an image to
+// the blobs of the layout, and returns the descriptor of the manifest.
+func (s *saveSession) saveOCIImage(id image.ID) (ociDescriptor, error) {
+	img, err := s.is.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	if len(img.RootFS.DiffIDs) == 0 {
+		return ociDescriptor{}, fmt.Errorf("empty export - not implemented")
+	}
+
+	config, err := s.writeOCIBlob(mediaTypeOCIConfig, bytes.NewReader(img.RawJSON()))
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	manifest := ociManifest{
+		SchemaVersion: 2,
+		MediaType:     mediaTypeOCIManifest,
+		Config:        config,
+	}
+	excluded := s.excludedLayersOf(*img.RootFS)
+	for i := range img.RootFS.DiffIDs {
+		rootFS := *img.RootFS
+		rootFS.DiffIDs = rootFS.DiffIDs[:i+1]
+		desc, err := s.saveOCILayer(rootFS.ChainID(), excluded[i])
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+		manifest.Layers = append(manifest.Layers, desc)
+	}
+
+	manifest.Annotations, err = s.is.GetAnnotations(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	desc, err := s.writeOCIBlob(mediaTypeOCIManifest, bytes.NewReader(manifestJSON))
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	desc.Platform = &ociPlatform{
+		Architecture: img.Architecture,
+		OS:           img.OS,
+		Variant:      img.Variant,
+	}
+	return desc, nil
+}
+
+// saveOCILayer writes the uncompressed tar stream of a layer to the blobs of
+// the layout, so that its digest is the DiffID of the layer. The blob of an
+// excluded layer is left out of the layout, only its descriptor is returned.
+func (s *saveSession) saveOCILayer(id layer.ChainID, excluded bool) (ociDescriptor, error) {
+	if desc, exists := s.savedOCILayers[id]; exists {
+		return desc, nil
+	}
+
+	l, err := s.ls.Get(id)
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer layer.ReleaseAndLog(s.ls, l)
+
+	arch, err := l.TarStream()
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	defer arch.Close()
+
+	var desc ociDescriptor
+	if excluded {
+		size, err := io.Copy(ioutil.Discard, arch)
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+		desc = ociDescriptor{
+			MediaType: mediaTypeOCILayer,
+			Digest:    digest.Digest(l.DiffID()),
+			Size:      size,
+		}
+	} else {
+		desc, err = s.writeOCIBlob(mediaTypeOCILayer, arch)
+		if err != nil {
+			return ociDescriptor{}, err
+		}
+	}
+	s.savedOCILayers[id] = desc
+	return desc, nil
+}
+
+// writeOCIBlob writes the content of r to the blobs of the layout, and
+// returns its descriptor.
+func (s *saveSession) writeOCIBlob(mediaType string, r io.Reader) (ociDescriptor, error) {
+	dir := filepath.Join(s.outDir, ociBlobsDirName, string(digest.Canonical))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return ociDescriptor{}, err
+	}
+
+	f, err := ioutil.TempFile(dir, "tmp-")
+	if err != nil {
+		return ociDescriptor{}, err
+	}
+	digester := digest.Canonical.New()
+	size, err := io.Copy(io.MultiWriter(f, digester.Hash()), r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return ociDescriptor{}, err
+	}
+
+	dgst := digester.Digest()
+	blobPath := filepath.Join(dir, dgst.Hex())
+	if err := os.Rename(f.Name(), blobPath); err != nil {
+		os.Remove(f.Name())
+		return ociDescriptor{}, err
+	}
+	if err := system.Chtimes(blobPath, time.Unix(0, 0), time.Unix(0, 0)); err != nil {
+		return ociDescriptor{}, err
+	}
+
+	return ociDescriptor{
+		MediaType: mediaType,
+		Digest:    dgst,
+		Size:      size,
+	}, nil
+}
+
+func writeJSONFile(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return err
+	}
+	return system.Chtimes(path, time.Unix(0, 0), time.Unix(0, 0))
+}
+
+// ociLoad loads the images of the OCI image layout in tmpDir, and tags them
+// with the references of the annotations of the index.
+func (l *tarexporter) ociLoad(tmpDir string, outStream io.Writer, progressOutput progress.Output) error {
+	var layout ociLayout
+	if err := readJSONFile(tmpDir, ociLayoutFileName, &layout); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(layout.ImageLayoutVersion, "1.") {
+		return fmt.Errorf("unsupported OCI image layout version %q", layout.ImageLayoutVersion)
+	}
+
+	var index ociIndex
+	if err := readJSONFile(tmpDir, ociIndexFileName, &index); err != nil {
+		return err
+	}
+
+	for _, desc := range index.Manifests {
+		imgID, err := l.ociLoadDescriptor(tmpDir, desc, progressOutput)
+		if err != nil {
+			return err
+		}
+		ref, err := ociReference(desc.Annotations)
+		if err != nil {
+			return err
+		}
+		if ref != nil {
+			if err := l.setLoadedTag(ref, imgID, outStream); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// ociLoadDescriptor loads the image desc refers to. If desc refers to an
+// index, the image for the platform of the daemon is loaded.
+func (l *tarexporter) ociLoadDescriptor(tmpDir string, desc ociDescriptor, progressOutput progress.Output) (image.ID, error) {
+	switch desc.MediaType {
+	case mediaTypeOCIManifest, schema2.MediaTypeManifest:
+		return l.ociLoadManifest(tmpDir, desc, progressOutput)
+	case mediaTypeOCIIndex, manifestlist.MediaTypeManifestList:
+		data, err := readOCIBlob(tmpDir, desc)
+		if err != nil {
+			return "", err
+		}
+		var index ociIndex
+		if err := json.Unmarshal(data, &index); err != nil {
+			return "", fmt.Errorf("invalid index %s: %v", desc.Digest, err)
+		}
+		for _, m := range index.Manifests {
+			if m.Platform == nil || (m.Platform.OS == runtime.GOOS && m.Platform.Architecture == runtime.GOARCH) {
+				return l.ociLoadDescriptor(tmpDir, m, progressOutput)
+			}
+		}
+		return "", fmt.Errorf("no image for platform %s/%s in index %s", runtime.GOOS, runtime.GOARCH, desc.Digest)
+	}
+	return "", fmt.Errorf("unsupported media type %q of %s", desc.MediaType, desc.Digest)
+}
+
+// ociLoadManifest loads the image of a manifest, and keeps the annotations
+// of the manifest with the image.
+func (l *tarexporter) ociLoadManifest(tmpDir string, desc ociDescriptor, progressOutput progress.Output) (image.ID, error) {
+	data, err := readOCIBlob(tmpDir, desc)
+	if err != nil {
+		return "", err
+	}
+	var manifest ociManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return "", fmt.Errorf("invalid manifest %s: %v", desc.Digest, err)
+	}
+
+	config, err := readOCIBlob(tmpDir, manifest.Config)
+	if err != nil {
+		return "", err
+	}
+
+	var layerPaths []string
+	for _, layerDesc := range manifest.Layers {
+		// Layers already in the layer store aren't read, so layers
+		// which aren't distributable or were excluded when saving
+		// may be left out of the layout.
+		layerPath, err := ociBlobPath(tmpDir, layerDesc.Digest)
+		if err != nil {
+			return "", err
+		}
+		layerPaths = append(layerPaths, layerPath)
+	}
+
+	imgID, err := l.loadImage(config, layerPaths, progressOutput)
+	if err != nil {
+		return "", err
+	}
+	if len(manifest.Annotations) > 0 {
+		if err := l.is.SetAnnotations(imgID, manifest.Annotations); err != nil {
+			return "", err
+		}
+	}
+	return imgID, nil
+}
+
+// ociReference returns the reference an index entry is tagged with by its
+// annotations, or nil if it isn't tagged. The org.opencontainers.image.ref.name
+// annotation only names a repository if it is a full reference.
+func ociReference(annotations map[string]string) (reference.NamedTagged, error) {
+	if name := annotations[ociImageNameAnnotation]; name != "" {
+		named, err := reference.ParseNamed(name)
+		if err != nil {
+			return nil, err
+		}
+		ref, ok := named.(reference.NamedTagged)
+		if !ok {
+			return nil, fmt.Errorf("invalid tag %q", name)
+		}
+		return ref, nil
+	}
+	if name := annotations[ociRefNameAnnotation]; name != "" {
+		if named, err := reference.ParseNamed(name); err == nil {
+			if ref, ok := named.(reference.NamedTagged); ok {
+				return ref, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func ociBlobPath(tmpDir string, dgst digest.Digest) (string, error) {
+	if err := dgst.Validate(); err != nil {
+		return "", err
+	}
+	return safePath(tmpDir, filepath.Join(ociBlobsDirName, string(dgst.Algorithm()), dgst.Hex()))
+}
+
+// readOCIBlob reads the blob desc refers to, and verifies its size and
+// digest.
+func readOCIBlob(tmpDir string, desc ociDescriptor) ([]byte, error) {
+	blobPath, err := ociBlobPath(tmpDir, desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadFile(blobPath)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) != desc.Size {
+		return nil, fmt.Errorf("invalid size of blob %s: expected %d, got %d", desc.Digest, desc.Size, len(data))
+	}
+	verifier, err := digest.NewDigestVerifier(desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	verifier.Write(data)
+	if !verifier.Verified() {
+		return nil, fmt.Errorf("invalid digest of blob %s", desc.Digest)
+	}
+	return data, nil
+}
+
+func readJSONFile(tmpDir, name string, v interface{}) error {
+	path, err := safePath(tmpDir, name)
+	if err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package tarexport
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/distribution/digest"
+)
+
+func TestValidateFormat(t *testing.T) {
+	for _, format := range []string{"", FormatDocker, FormatOCI} {
+		if err := ValidateFormat(format); err != nil {
+			t.Fatalf("Expected format %q to be valid, got error: %v", format, err)
+		}
+	}
+	if err := ValidateFormat("zip"); err == nil {
+		t.Fatal("Expected format zip to be invalid")
+	}
+}
+
+func TestOCIReference(t *testing.T) {
+	valids := map[string]map[string]string{
+		"docker.io/library/busybox:latest": {ociImageNameAnnotation: "busybox:latest", ociRefNameAnnotation: "latest"},
+		"example.com/app:v1":               {ociRefNameAnnotation: "example.com/app:v1"},
+	}
+	for expected, annotations := range valids {
+		ref, err := ociReference(annotations)
+		if err != nil {
+			t.Fatalf("Expected %v to be valid, got error: %v", annotations, err)
+		}
+		if ref == nil || ref.FullName()+":"+ref.Tag() != expected {
+			t.Fatalf("Expected %v to refer to %s, got %v", annotations, expected, ref)
+		}
+	}
+
+	// A tag alone doesn't name a repository.
+	for _, annotations := range []map[string]string{nil, {ociRefNameAnnotation: "v1"}} {
+		ref, err := ociReference(annotations)
+		if err != nil || ref != nil {
+			t.Fatalf("Expected %v not to be tagged, got %v, %v", annotations, ref, err)
+		}
+	}
+
+	for _, name := range []string{"busybox", "Invalid:latest"} {
+		if _, err := ociReference(map[string]string{ociImageNameAnnotation: name}); err == nil {
+			t.Fatalf("Expected image name %q to be invalid", name)
+		}
+	}
+}
+
+func TestReadOCIBlob(t *testing.T) {
+	tmpDir, err := ioutil.TempDir("", "oci-layout-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	content := []byte(`{"schemaVersion":2}`)
+	dgst := digest.FromBytes(content)
+	blobDir := filepath.Join(tmpDir, ociBlobsDirName, string(dgst.Algorithm()))
+	if err := os.MkdirAll(blobDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(blobDir, dgst.Hex()), content, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := readOCIBlob(tmpDir, ociDescriptor{Digest: dgst, Size: int64(len(content))})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != string(content) {
+		t.Fatalf("Expected %q, got %q", content, data)
+	}
+
+	if _, err := readOCIBlob(tmpDir, ociDescriptor{Digest: dgst, Size: 1}); err == nil {
+		t.Fatal("Expected a blob of the wrong size to be rejected")
+	}
+
+	// A blob stored under another digest is corrupted.
+	other := digest.FromBytes([]byte("other"))
+	if err := os.Rename(filepath.Join(blobDir, dgst.Hex()), filepath.Join(blobDir, other.Hex())); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readOCIBlob(tmpDir, ociDescriptor{Digest: other, Size: int64(len(content))}); err == nil {
+		t.Fatal("Expected a blob with the wrong digest to be rejected")
+	}
+}
@@ -25,18 +25,33 @@ type imageDescriptor struct {
 
 type saveSession struct {
 	*tarexporter
-	outDir      string
-	images      map[image.ID]*imageDescriptor
-	savedLayers map[string]struct{}
+	outDir         string
+	images         map[image.ID]*imageDescriptor
+	savedLayers    map[string]struct{}
+	savedOCILayers map[layer.ChainID]ociDescriptor
+	excludedLayers map[layer.ChainID]struct{}
 }
 
-func (l *tarexporter) Save(names []string, outStream io.Writer) error {
+func (l *tarexporter) Save(names, excludes []string, format string, outStream io.Writer) error {
+	if err := ValidateFormat(format); err != nil {
+		return err
+	}
+
 	images, err := l.parseNames(names)
 	if err != nil {
 		return err
 	}
 
-	return (&saveSession{tarexporter: l, images: images}).save(outStream)
+	excludedLayers, err := l.parseExcludes(excludes)
+	if err != nil {
+		return err
+	}
+
+	s := &saveSession{tarexporter: l, images: images, excludedLayers: excludedLayers}
+	if format == FormatOCI {
+		return s.saveOCI(outStream)
+	}
+	return s.save(outStream)
 }
 
 func (l *tarexporter) parseNames(names []string) (map[image.ID]*imageDescriptor, error) {
@@ -114,6 +129,53 @@ func (l *tarexporter) parseNames(names []string) (map[image.ID]*imageDescriptor,
 	return imgDescr, nil
 }
 
+// parseExcludes returns the chain IDs of the layers to leave out of the
+// archive. An exclude is either an image, all of whose layers are left out,
+// or the chain ID of a layer, which is left out with its parents.
+func (l *tarexporter) parseExcludes(excludes []string) (map[layer.ChainID]struct{}, error) {
+	excludedLayers := make(map[layer.ChainID]struct{})
+	for _, exclude := range excludes {
+		imgs, err := l.parseNames([]string{exclude})
+		if err != nil {
+			chainID, dgstErr := digest.ParseDigest(exclude)
+			if dgstErr != nil {
+				return nil, fmt.Errorf("invalid layer exclusion %q: not an image or a chain ID: %v", exclude, err)
+			}
+			excludedLayers[layer.ChainID(chainID)] = struct{}{}
+			continue
+		}
+		for id := range imgs {
+			img, err := l.is.Get(id)
+			if err != nil {
+				return nil, err
+			}
+			rootFS := *img.RootFS
+			for i := range img.RootFS.DiffIDs {
+				rootFS.DiffIDs = img.RootFS.DiffIDs[:i+1]
+				excludedLayers[rootFS.ChainID()] = struct{}{}
+			}
+		}
+	}
+	return excludedLayers, nil
+}
+
+// excludedLayersOf returns, for each layer of rootFS, whether it is left out of
+// the archive. The parents of an excluded layer are excluded too.
+func (s *saveSession) excludedLayersOf(rootFS image.RootFS) []bool {
+	excluded := make([]bool, len(rootFS.DiffIDs))
+	diffIDs := rootFS.DiffIDs
+	for i := len(diffIDs) - 1; i >= 0; i-- {
+		rootFS.DiffIDs = diffIDs[:i+1]
+		if _, ok := s.excludedLayers[rootFS.ChainID()]; ok {
+			for j := 0; j <= i; j++ {
+				excluded[j] = true
+			}
+			break
+		}
+	}
+	return excluded
+}
+
 func (s *saveSession) save(outStream io.Writer) error {
 	s.savedLayers = make(map[string]struct{})
 
@@ -224,6 +286,7 @@ func (s *saveSession) saveImage(id image.ID) error {
 
 	var parent digest.Digest
 	var layers []string
+	excluded := s.excludedLayersOf(*img.RootFS)
 	for i := range img.RootFS.DiffIDs {
 		v1Img := image.V1Image{}
 		if i == len(img.RootFS.DiffIDs)-1 {
@@ -241,8 +304,12 @@ func (s *saveSession) saveImage(id image.ID) error {
 			v1Img.Parent = parent.Hex()
 		}
 
-		if err := s.saveLayer(rootFS.ChainID(), v1Img, img.Created); err != nil {
-			return err
+		// Excluded layers are listed in the manifest, but their
+		// directories are left out of the archive.
+		if !excluded[i] {
+			if err := s.saveLayer(rootFS.ChainID(), v1Img, img.Created); err != nil {
+				return err
+			}
 		}
 		layers = append(layers, v1Img.ID)
 		parent = v1ID
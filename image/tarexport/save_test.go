@@ -0,0 +1,33 @@
+package tarexport
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/docker/docker/image"
+	"github.com/docker/docker/layer"
+)
+
+func TestExcludedLayersOf(t *testing.T) {
+	rootFS := *image.NewRootFS()
+	for _, diffID := range []layer.DiffID{
+		"sha256:1111111111111111111111111111111111111111111111111111111111111111",
+		"sha256:2222222222222222222222222222222222222222222222222222222222222222",
+		"sha256:3333333333333333333333333333333333333333333333333333333333333333",
+	} {
+		rootFS.Append(diffID)
+	}
+	base := rootFS
+	base.DiffIDs = rootFS.DiffIDs[:2]
+
+	s := &saveSession{excludedLayers: map[layer.ChainID]struct{}{}}
+	if excluded := s.excludedLayersOf(rootFS); !reflect.DeepEqual(excluded, []bool{false, false, false}) {
+		t.Fatalf("Expected no layer to be excluded, got %v", excluded)
+	}
+
+	// Excluding a layer excludes its parents, but not its children.
+	s.excludedLayers[base.ChainID()] = struct{}{}
+	if excluded := s.excludedLayersOf(rootFS); !reflect.DeepEqual(excluded, []bool{true, true, false}) {
+		t.Fatalf("Expected the 2 base layers to be excluded, got %v", excluded)
+	}
+}
@@ -1,11 +1,21 @@
 package tarexport
 
 import (
+	"fmt"
+
 	"github.com/docker/docker/image"
 	"github.com/docker/docker/layer"
 	"github.com/docker/docker/reference"
 )
 
+const (
+	// FormatDocker is the format of the archives written by docker save
+	// by default, with a manifest.json file and a directory for each layer.
+	FormatDocker = "docker"
+	// FormatOCI is the format of the archives holding an OCI image layout.
+	FormatOCI = "oci"
+)
+
 const (
 	manifestFileName           = "manifest.json"
 	legacyLayerFileName        = "layer.tar"
@@ -35,3 +45,13 @@ func NewTarExporter(is image.Store, ls layer.Store, rs reference.Store) image.Ex
 		rs: rs,
 	}
 }
+
+// ValidateFormat checks that format is a format images can be saved in. An
+// empty format is the default format, FormatDocker.
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatDocker, FormatOCI:
+		return nil
+	}
+	return fmt.Errorf("invalid format %q, must be %s or %s", format, FormatDocker, FormatOCI)
+}
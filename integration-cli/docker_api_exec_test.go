@@ -152,6 +152,67 @@ func (s *DockerSuite) TestExecApiStartWithDetach(c *check.C) {
 	}
 }
 
+func (s *DockerSuite) TestExecApiKillAndList(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "exec_kill_test"
+	runSleepingContainer(c, "-d", "--name", name)
+
+	status, b, err := sockRequest("POST", fmt.Sprintf("/containers/%s/exec", name), map[string]interface{}{"Cmd": []string{"sleep", "100"}})
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusCreated, check.Commentf(string(b)))
+	createResp := struct {
+		ID string `json:"Id"`
+	}{}
+	c.Assert(json.Unmarshal(b, &createResp), checker.IsNil, check.Commentf(string(b)))
+	execID := createResp.ID
+
+	// An exec which isn't running can't be killed.
+	status, b, err = sockRequest("POST", fmt.Sprintf("/exec/%s/kill", execID), nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusConflict, check.Commentf(string(b)))
+
+	startExec(c, execID, http.StatusOK)
+
+	var execs []struct {
+		ID      string
+		Running bool
+	}
+	status, b, err = sockRequest("GET", fmt.Sprintf("/containers/%s/execs", name), nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusOK, check.Commentf(string(b)))
+	c.Assert(json.Unmarshal(b, &execs), checker.IsNil, check.Commentf(string(b)))
+	c.Assert(execs, checker.HasLen, 1)
+	c.Assert(execs[0].ID, checker.Equals, execID)
+	c.Assert(execs[0].Running, checker.True)
+
+	status, b, err = sockRequest("POST", fmt.Sprintf("/exec/%s/kill?signal=SIGTERM", execID), nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusNoContent, check.Commentf(string(b)))
+
+	var inspectJSON struct {
+		Running  bool
+		ExitCode *int
+	}
+	for i := 0; ; i++ {
+		inspectExec(c, execID, &inspectJSON)
+		if !inspectJSON.Running {
+			break
+		}
+		if i >= 50 {
+			c.Fatal("exec was not killed")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	c.Assert(inspectJSON.ExitCode, checker.NotNil)
+	c.Assert(*inspectJSON.ExitCode, checker.Not(checker.Equals), 0)
+
+	status, b, err = sockRequest("GET", fmt.Sprintf("/containers/%s/execs", name), nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusOK, check.Commentf(string(b)))
+	c.Assert(json.Unmarshal(b, &execs), checker.IsNil, check.Commentf(string(b)))
+	c.Assert(execs, checker.HasLen, 0)
+}
+
 func createExec(c *check.C, name string) string {
 	_, b, err := sockRequest("POST", fmt.Sprintf("/containers/%s/exec", name), map[string]interface{}{"Cmd": []string{"true"}})
 	c.Assert(err, checker.IsNil, check.Commentf(string(b)))
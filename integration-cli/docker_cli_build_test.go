@@ -6884,3 +6884,280 @@ func (s *DockerRegistryAuthHtpasswdSuite) TestBuildWithExternalAuth(c *check.C)
 	out, _, err := runCommandWithOutput(buildCmd)
 	c.Assert(err, check.IsNil, check.Commentf(out))
 }
+
+func (s *DockerSuite) TestBuildOutputLocal(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	ctx, err := fakeContext(`FROM busybox
+RUN mkdir -p /out && echo hello > /out/result`, nil)
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	dest, err := ioutil.TempDir("", "docker-build-output")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(dest)
+
+	out, _, err := dockerCmdInDir(c, ctx.Dir, "build", "--output", "type=local,dest="+dest+",src=/out", ".")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	content, err := ioutil.ReadFile(filepath.Join(dest, "out", "result"))
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(content), checker.Equals, "hello\n")
+}
+
+func (s *DockerSuite) TestBuildOutputTar(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	ctx, err := fakeContext(`FROM busybox
+RUN mkdir -p /out && echo hello > /out/result`, nil)
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	dest, err := ioutil.TempDir("", "docker-build-output")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(dest)
+	tarPath := filepath.Join(dest, "out.tar")
+
+	out, _, err := dockerCmdInDir(c, ctx.Dir, "build", "--output", "type=tar,dest="+tarPath+",src=/out/result", ".")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	f, err := os.Open(tarPath)
+	c.Assert(err, checker.IsNil)
+	defer f.Close()
+
+	tr := tar.NewReader(f)
+	hdr, err := tr.Next()
+	c.Assert(err, checker.IsNil)
+	c.Assert(hdr.Name, checker.Equals, "result")
+	content, err := ioutil.ReadAll(tr)
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(content), checker.Equals, "hello\n")
+}
+
+func (s *DockerSuite) TestBuildOutputLeavesNoImage(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	ctx, err := fakeContext(`FROM busybox
+RUN mkdir -p /out && echo leavesnoimage > /out/result
+RUN echo done > /out/done`, nil)
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	dest, err := ioutil.TempDir("", "docker-build-output")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(dest)
+
+	// Make sure busybox is present, so only the images of the build
+	// could show up.
+	dockerCmd(c, "inspect", "busybox")
+	before, _ := dockerCmd(c, "images", "-a", "-q", "--no-trunc")
+
+	out, _, err := dockerCmdInDir(c, ctx.Dir, "build", "--output", "type=local,dest="+dest+",src=/out", ".")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	after, _ := dockerCmd(c, "images", "-a", "-q", "--no-trunc")
+	c.Assert(after, checker.Equals, before)
+}
+
+func (s *DockerSuite) TestBuildOutputKeepsExistingImages(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildoutputkeepsexistingimages"
+	dockerfile := `FROM busybox
+RUN mkdir -p /out && echo keepsexistingimages > /out/result`
+
+	dest, err := ioutil.TempDir("", "docker-build-output")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(dest)
+
+	// The result of a build made only of FROM is the base image itself.
+	ctx, err := fakeContext("FROM busybox", nil)
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+	out, _, err := dockerCmdInDir(c, ctx.Dir, "build", "--output", "type=local,dest="+dest+",src=/etc", ".")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	dockerCmd(c, "inspect", "busybox")
+
+	// The result of a fully cached build is an image tagged before.
+	id, err := buildImage(name, dockerfile, true)
+	c.Assert(err, checker.IsNil)
+	before, _ := dockerCmd(c, "images", "-a", "-q", "--no-trunc")
+
+	ctx, err = fakeContext(dockerfile, nil)
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+	out, _, err = dockerCmdInDir(c, ctx.Dir, "build", "--output", "type=local,dest="+dest+",src=/out", ".")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	after, _ := dockerCmd(c, "images", "-a", "-q", "--no-trunc")
+	c.Assert(after, checker.Equals, before)
+	c.Assert(inspectField(c, name, "Id"), checker.Equals, id)
+}
+
+func (s *DockerSuite) TestBuildOutputWithTagFails(c *check.C) {
+	out, _, err := dockerCmdWithError("build", "--output", "type=tar,dest=out.tar", "-t", "testbuildoutput", "-")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Conflicting options: --output and --tag")
+}
+
+func (s *DockerSuite) TestBuildCopyChownChmod(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildcopychownchmod"
+	ctx, err := fakeContext(`FROM busybox
+RUN echo 'dockerio:x:1001:1001::/bin:/bin/false' >> /etc/passwd
+RUN echo 'dockerio:x:1001:' >> /etc/group
+COPY --chown=dockerio --chmod=0750 test_file /copied
+ADD --chown=1002:1003 --chmod=0600 test_dir /added
+RUN [ $(stat -c %u:%g:%a /copied) = '1001:1001:750' ]
+RUN [ $(stat -c %u:%g:%a /added) = '1002:1003:600' ]
+RUN [ $(stat -c %u:%g:%a /added/nested) = '1002:1003:600' ]`,
+		map[string]string{
+			"test_file":       "test1",
+			"test_dir/nested": "test2",
+		})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, err = buildImageFromContext(name, ctx, true)
+	c.Assert(err, checker.IsNil)
+}
+
+func (s *DockerSuite) TestBuildCopyChownUnknownUser(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildcopychownunknownuser"
+	ctx, err := fakeContext(`FROM busybox
+COPY --chown=nosuchuser test_file /`,
+		map[string]string{
+			"test_file": "test1",
+		})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, out, err := buildImageFromContextWithOut(name, ctx, true)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "nosuchuser")
+}
+
+func (s *DockerSuite) TestBuildAddRemoteFileWithChecksum(c *check.C) {
+	testRequires(c, DaemonIsLinux) // Windows doesn't have httpserver image yet
+	name := "testbuildaddremotefilewithchecksum"
+
+	server, err := fakeStorage(map[string]string{
+		"file": "hello",
+	})
+	c.Assert(err, checker.IsNil)
+	defer server.Close()
+
+	// sha256 of "hello"
+	checksum := "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	dFmt := `FROM busybox
+ADD --checksum=%s %s/file /`
+
+	id1, err := buildImage(name, fmt.Sprintf(dFmt, checksum, server.URL()), true)
+	c.Assert(err, checker.IsNil)
+
+	// A new server changes the Last-Modified time of the file, but the
+	// cache is keyed on the checksum.
+	server2, err := fakeStorage(map[string]string{
+		"file": "hello",
+	})
+	c.Assert(err, checker.IsNil)
+	defer server2.Close()
+
+	id2, err := buildImage(name, fmt.Sprintf(dFmt, checksum, server2.URL()), true)
+	c.Assert(err, checker.IsNil)
+	c.Assert(id2, checker.Equals, id1, check.Commentf("The cache should have been used"))
+
+	wrongChecksum := "sha256:0000000000000000000000000000000000000000000000000000000000000000"
+	_, out, err := buildImageWithOut(name, fmt.Sprintf(dFmt, wrongChecksum, server.URL()), true)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Checksum mismatch")
+}
+
+func (s *DockerSuite) TestBuildAddChecksumLocalFile(c *check.C) {
+	name := "testbuildaddchecksumlocalfile"
+	ctx, err := fakeContext(`FROM `+minimalBaseImage()+`
+ADD --checksum=sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824 file /`,
+		map[string]string{
+			"file": "hello",
+		})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, out, err := buildImageFromContextWithOut(name, ctx, true)
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "--checksum can only be used with URL sources")
+}
+
+func (s *DockerSuite) TestBuildNetworkNone(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildnetworknone"
+	// With no network, the only interface of the build container is the
+	// loopback interface.
+	_, err := buildImage(name, `FROM busybox
+RUN [ "$(ls /sys/class/net)" = "lo" ]`, false, "--network=none")
+	c.Assert(err, checker.IsNil)
+}
+
+func (s *DockerSuite) TestBuildNetworkUserDefined(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	dockerCmd(c, "network", "create", "--subnet=172.28.0.0/16", "testbuildnet")
+	defer dockerCmd(c, "network", "rm", "testbuildnet")
+
+	name := "testbuildnetworkuserdefined"
+	_, err := buildImage(name, `FROM busybox
+RUN ip -o -4 addr show | grep -q 'inet 172\.28\.'`, false, "--network=testbuildnet")
+	c.Assert(err, checker.IsNil)
+}
+
+func (s *DockerSuite) TestBuildAddHost(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuildaddhost"
+	_, err := buildImage(name, `FROM busybox
+RUN grep -q '^10\.0\.0\.5[[:space:]]*proxy\.example\.com$' /etc/hosts`, false, "--add-host=proxy.example.com:10.0.0.5")
+	c.Assert(err, checker.IsNil)
+}
+
+func (s *DockerSuite) TestBuildDryRun(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "testbuilddryrun"
+	ctx, err := fakeContext(`FROM busybox
+ARG VERSION
+COPY . /app
+RUN touch /created
+LABEL version=$VERSION`,
+		map[string]string{
+			"file": "test1",
+		})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, out, err := buildImageFromContextWithOut(name, ctx, true, "--dry-run")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "COPY of the whole build context")
+	c.Assert(out, checker.Contains, "Variable VERSION is declared by ARG but has no value")
+	c.Assert(out, checker.Contains, "Dry run found 0 error(s) and 2 warning(s)")
+
+	// A dry run doesn't create any image.
+	_, err = inspectFieldWithError(name, "Id")
+	c.Assert(err, checker.NotNil)
+}
+
+func (s *DockerSuite) TestBuildDryRunReportsAllErrors(c *check.C) {
+	name := "testbuilddryrunerrors"
+	ctx, err := fakeContext(`FROM `+minimalBaseImage()+`
+COPY missing /
+COPY ignored /
+ADD --unknown=1 file /`,
+		map[string]string{
+			"file":          "test1",
+			"ignored":       "test2",
+			".dockerignore": "ignored",
+		})
+	c.Assert(err, checker.IsNil)
+	defer ctx.Close()
+
+	_, out, err := buildImageFromContextWithOut(name, ctx, true, "--dry-run", "--build-arg", "UNDECLARED=1")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "missing: no such file or directory")
+	c.Assert(out, checker.Contains, "(excluded by .dockerignore)")
+	c.Assert(out, checker.Contains, "Unknown flag: unknown")
+	c.Assert(out, checker.Contains, "build-args [UNDECLARED] were not consumed")
+	c.Assert(out, checker.Contains, "The Dockerfile has 4 error(s), the build would fail")
+}
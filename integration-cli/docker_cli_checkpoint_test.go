@@ -0,0 +1,91 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestCheckpointCreateAndRestore(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon, Criu)
+
+	name := "checkpointed"
+	dockerCmd(c, "run", "-d", "--name", name, "busybox", "sh", "-c", "while true; do sleep 1; done")
+	c.Assert(waitRun(name), checker.IsNil)
+
+	out, _ := dockerCmd(c, "checkpoint", "create", name, "checkpoint1")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "checkpoint1")
+	// the container exits once it's checkpointed
+	c.Assert(inspectField(c, name, "State.Running"), checker.Equals, "false")
+
+	out, _ = dockerCmd(c, "checkpoint", "ls", name)
+	c.Assert(out, checker.Contains, "checkpoint1")
+
+	dockerCmd(c, "start", "--checkpoint", "checkpoint1", name)
+	c.Assert(waitRun(name), checker.IsNil)
+
+	out, _ = dockerCmd(c, "checkpoint", "rm", name, "checkpoint1")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "checkpoint1")
+	out, _ = dockerCmd(c, "checkpoint", "ls", name)
+	c.Assert(out, checker.Not(checker.Contains), "checkpoint1")
+}
+
+func (s *DockerSuite) TestCheckpointCreateLeaveRunning(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon, Criu)
+
+	name := "checkpointed"
+	dockerCmd(c, "run", "-d", "--name", name, "busybox", "sh", "-c", "while true; do sleep 1; done")
+	c.Assert(waitRun(name), checker.IsNil)
+
+	dockerCmd(c, "checkpoint", "create", "--leave-running", name, "checkpoint1")
+	c.Assert(inspectField(c, name, "State.Running"), checker.Equals, "true")
+}
+
+func (s *DockerSuite) TestCheckpointListNone(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	name := "notcheckpointed"
+	dockerCmd(c, "run", "-d", "--name", name, "busybox", "true")
+
+	out, _ := dockerCmd(c, "checkpoint", "ls", name)
+	c.Assert(strings.TrimSpace(out), checker.Equals, "CHECKPOINT NAME")
+}
+
+func (s *DockerSuite) TestCheckpointCreateNotRunning(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	name := "notrunning"
+	dockerCmd(c, "create", "--name", name, "busybox", "true")
+
+	out, _, err := dockerCmdWithError("checkpoint", "create", name, "checkpoint1")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "is not running")
+}
+
+func (s *DockerSuite) TestStartCheckpointMultipleContainers(c *check.C) {
+	out, _, err := dockerCmdWithError("start", "--checkpoint", "checkpoint1", "first", "second")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "You cannot restore multiple containers at once.")
+}
+
+func (s *DockerSuite) TestCheckpointInvalidName(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	name := "checkpointinvalidname"
+	dockerCmd(c, "create", "--name", name, "busybox", "true")
+
+	for _, checkpoint := range []string{"../..", "foo/bar", ".."} {
+		out, _, err := dockerCmdWithError("checkpoint", "rm", name, checkpoint)
+		c.Assert(err, checker.NotNil)
+		c.Assert(out, checker.Contains, "must not contain '/' or '..'")
+
+		out, _, err = dockerCmdWithError("start", "--checkpoint", checkpoint, name)
+		c.Assert(err, checker.NotNil)
+		c.Assert(out, checker.Contains, "must not contain '/' or '..'")
+	}
+
+	out, _, err := dockerCmdWithError("checkpoint", "rm", name, "missing")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "No such checkpoint: missing")
+}
@@ -20,6 +20,8 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/docker/distribution/digest"
+	"github.com/docker/docker/layer"
 	"github.com/docker/docker/pkg/integration/checker"
 	"github.com/docker/go-units"
 	"github.com/docker/libnetwork/iptables"
@@ -2222,3 +2224,186 @@ func (s *DockerSuite) TestDaemonDiscoveryBackendConfigReload(c *check.C) {
 	c.Assert(out, checker.Contains, fmt.Sprintf("Cluster Store: consul://consuladdr:consulport/some/path"))
 	c.Assert(out, checker.Contains, fmt.Sprintf("Cluster Advertise: 192.168.56.100:0"))
 }
+
+// TestDaemonTrustPolicy checks the daemon refuses to run or load images of
+// a repository its trust policy covers, if they weren't pulled by a signed
+// digest, and logs the refusals as events.
+func (s *DockerDaemonSuite) TestDaemonTrustPolicy(c *check.C) {
+	c.Assert(s.d.StartWithBusybox(), checker.IsNil)
+	archivePath := filepath.Join(s.d.folder, "busybox.tar")
+	out, err := s.d.Cmd("save", "-o", archivePath, "busybox:latest")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+
+	policyPath := filepath.Join(s.d.folder, "trust-policy.json")
+	policy := `{"repositories": [{"name": "docker.io/library/*"}]}`
+	c.Assert(ioutil.WriteFile(policyPath, []byte(policy), 0644), checker.IsNil)
+	c.Assert(s.d.Restart("--trust-policy", policyPath), checker.IsNil)
+
+	out, err = s.d.Cmd("run", "--rm", "busybox", "true")
+	c.Assert(err, checker.NotNil, check.Commentf("expected an unsigned image to be refused: %s", out))
+	c.Assert(out, checker.Contains, "refused by the trust policy")
+
+	out, err = s.d.Cmd("rmi", "busybox:latest")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	// The loaded image has the same content as a signed one, but an
+	// archive has no signed digest to check.
+	out, err = s.d.Cmd("load", "-i", archivePath)
+	c.Assert(err, checker.NotNil, check.Commentf("expected a loaded image not to be tagged: %s", out))
+	c.Assert(out, checker.Contains, "refused by the trust policy")
+	c.Assert(out, checker.Contains, "images loaded from an archive have no signed digest")
+	out, err = s.d.Cmd("inspect", "busybox:latest")
+	c.Assert(err, checker.NotNil, check.Commentf("expected busybox:latest not to be tagged: %s", out))
+
+	out, err = s.d.Cmd("events", "--since", "0", "--until", strconv.FormatInt(time.Now().Unix(), 10), "--filter", "event=untrusted")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "operation=create")
+	c.Assert(out, checker.Contains, "operation=load")
+}
+
+func (s *DockerDaemonSuite) TestDaemonTrustPolicyInvalid(c *check.C) {
+	policyPath := filepath.Join(s.d.folder, "trust-policy.json")
+	c.Assert(ioutil.WriteFile(policyPath, []byte(`{"repositories": [{"name": "busybox:latest"}]}`), 0644), checker.IsNil)
+	c.Assert(s.d.Start("--trust-policy", policyPath), checker.NotNil)
+	content, err := ioutil.ReadFile(s.d.logFile.Name())
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(content), checker.Contains, "invalid trust policy")
+}
+
+// TestDaemonVerifyLayers corrupts the metadata of a layer, and checks it is
+// reported on startup with --verify-layers and by docker image verify.
+func (s *DockerDaemonSuite) TestDaemonVerifyLayers(c *check.C) {
+	c.Assert(s.d.StartWithBusybox(), checker.IsNil)
+	name := "test-verify-layers"
+	out, err := s.d.Cmd("run", "--name", name, "busybox", "sh", "-c", "echo corrupt > /file")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	out, err = s.d.Cmd("commit", name, name)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	imageID := strings.TrimSpace(out)
+
+	out, err = s.d.Cmd("inspect", "--format", "{{json .RootFS.Layers}}", name)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	var diffIDs []layer.DiffID
+	c.Assert(json.Unmarshal([]byte(out), &diffIDs), checker.IsNil)
+	c.Assert(len(diffIDs), checker.GreaterThan, 1)
+	chainID := layer.CreateChainID(diffIDs)
+	out, err = s.d.Cmd("inspect", "--format", "{{.GraphDriver.Name}}", name)
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	driver := strings.TrimSpace(out)
+
+	// Store the DiffID of the base layer for the top layer.
+	c.Assert(s.d.Stop(), checker.IsNil)
+	diffPath := filepath.Join(s.d.root, "image", driver, "layerdb", "sha256", digest.Digest(chainID).Hex(), "diff")
+	c.Assert(ioutil.WriteFile(diffPath, []byte(diffIDs[0]), 0644), checker.IsNil)
+
+	c.Assert(s.d.Start("--verify-layers"), checker.IsNil)
+	content, err := ioutil.ReadFile(s.d.logFile.Name())
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(content), checker.Contains, fmt.Sprintf("Layer %s is corrupt", chainID))
+
+	out, err = s.d.Cmd("image", "verify", "--no-trunc")
+	c.Assert(err, checker.NotNil, check.Commentf("expected a corrupt layer to be reported: %s", out))
+	c.Assert(out, checker.Contains, chainID.String())
+	c.Assert(out, checker.Contains, imageID)
+
+	out, err = s.d.Cmd("image", "verify", "busybox")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "none is corrupt")
+}
+
+func (s *DockerDaemonSuite) TestRunWithRuntimeFromFlags(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	c.Assert(s.d.StartWithBusybox("--add-runtime", "oci=docker-runc", "--add-runtime", "vm=/usr/local/bin/vm-manager"), checker.IsNil)
+
+	out, err := s.d.Cmd("info")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "Runtimes: oci runc vm")
+
+	// Run with the registered runtime
+	out, err = s.d.Cmd("run", "--rm", "--runtime=oci", "busybox", "ls")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+
+	// Run with the default runtime
+	out, err = s.d.Cmd("run", "--rm", "--runtime=runc", "busybox", "ls")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+
+	// Run with an unknown runtime
+	out, err = s.d.Cmd("run", "--rm", "--runtime=invalid", "busybox", "ls")
+	c.Assert(err, check.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "Unknown runtime specified invalid")
+
+	// A container created with a runtime that disappeared can't be started
+	out, err = s.d.Cmd("create", "--name", "vmcontainer", "--runtime=vm", "busybox", "ls")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	c.Assert(s.d.Restart("--add-runtime", "oci=docker-runc"), checker.IsNil)
+	out, err = s.d.Cmd("start", "vmcontainer")
+	c.Assert(err, check.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "No such runtime 'vm'")
+}
+
+func (s *DockerDaemonSuite) TestRunWithRuntimeIsUsed(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+	dir, err := ioutil.TempDir("", "docker-runtime")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(dir)
+
+	// The runtime records its arguments before handing over to runc.
+	marker := filepath.Join(dir, "args")
+	runtime := filepath.Join(dir, "marker-runc")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$@\" >> %s\nexec docker-runc \"$@\"\n", marker)
+	c.Assert(ioutil.WriteFile(runtime, []byte(script), 0755), checker.IsNil)
+	c.Assert(s.d.StartWithBusybox("--add-runtime", "marker="+runtime), checker.IsNil)
+
+	out, err := s.d.Cmd("run", "--rm", "--runtime=marker", "busybox", "true")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	args, err := ioutil.ReadFile(marker)
+	c.Assert(err, checker.IsNil, check.Commentf("the container wasn't run with its runtime"))
+	c.Assert(string(args), checker.Contains, "start")
+
+	// The default runtime doesn't go through it
+	c.Assert(os.Remove(marker), checker.IsNil)
+	out, err = s.d.Cmd("run", "--rm", "busybox", "true")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+	_, err = os.Stat(marker)
+	c.Assert(os.IsNotExist(err), checker.True)
+}
+
+func (s *DockerDaemonSuite) TestRunWithRuntimeFromConfigFile(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	configFile := filepath.Join(s.d.folder, "daemon.json")
+	config := `{"runtimes": {"oci": {"path": "docker-runc"}}}`
+	c.Assert(ioutil.WriteFile(configFile, []byte(config), 0644), checker.IsNil)
+	c.Assert(s.d.StartWithBusybox("--config-file", configFile), checker.IsNil)
+
+	out, err := s.d.Cmd("run", "--rm", "--runtime=oci", "busybox", "ls")
+	c.Assert(err, check.IsNil, check.Commentf(out))
+}
+
+func (s *DockerDaemonSuite) TestDaemonRejectsReservedRuntimeName(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	c.Assert(s.d.Start("--add-runtime", "runc=/usr/local/bin/runc"), checker.NotNil)
+	content, _ := ioutil.ReadFile(s.d.logFile.Name())
+	c.Assert(string(content), checker.Contains, "runtime name 'runc' is reserved")
+}
+
+func (s *DockerDaemonSuite) TestDaemonWithInit(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	c.Assert(s.d.StartWithBusybox("--init"), checker.IsNil)
+
+	out, err := s.d.Cmd("run", "--rm", "busybox", "cat", "/proc/1/cmdline")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "/dev/init")
+
+	// The daemon setting can be overridden per container
+	out, err = s.d.Cmd("run", "--rm", "--init=false", "busybox", "cat", "/proc/1/cmdline")
+	c.Assert(err, checker.IsNil, check.Commentf(out))
+	c.Assert(out, checker.Not(checker.Contains), "/dev/init")
+}
+
+func (s *DockerDaemonSuite) TestDaemonWithInitPathNotFound(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	c.Assert(s.d.StartWithBusybox("--init-path", "/does/not/exist"), checker.IsNil)
+
+	out, err := s.d.Cmd("run", "--rm", "--init", "busybox", "true")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "could not find init binary")
+}
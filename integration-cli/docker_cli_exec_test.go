@@ -118,6 +118,35 @@ func (s *DockerSuite) TestExecEnv(c *check.C) {
 	c.Assert(out, checker.Contains, "HOME=/root")
 }
 
+func (s *DockerSuite) TestExecEnvOverride(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	runSleepingContainer(c, "-e", "LALA=value1", "-e", "FOO=bar", "-d", "--name", "testing")
+	c.Assert(waitRun("testing"), check.IsNil)
+
+	out, _ := dockerCmd(c, "exec", "-e", "LALA=value2", "-e", "BAZ=qux", "testing", "env")
+	c.Assert(out, checker.Not(checker.Contains), "LALA=value1")
+	c.Assert(out, checker.Contains, "LALA=value2")
+	c.Assert(out, checker.Contains, "FOO=bar")
+	c.Assert(out, checker.Contains, "BAZ=qux")
+	c.Assert(out, checker.Contains, "HOME=/root")
+}
+
+func (s *DockerSuite) TestExecWorkingDir(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	runSleepingContainer(c, "-w", "/root", "-d", "--name", "testing")
+	c.Assert(waitRun("testing"), check.IsNil)
+
+	out, _ := dockerCmd(c, "exec", "testing", "pwd")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "/root")
+
+	out, _ = dockerCmd(c, "exec", "-w", "/tmp", "testing", "pwd")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "/tmp")
+
+	out, _, err := dockerCmdWithError("exec", "-w", "tmp", "testing", "pwd")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "It needs to be an absolute path")
+}
+
 func (s *DockerSuite) TestExecExitStatus(c *check.C) {
 	runSleepingContainer(c, "-d", "--name", "top")
 
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestImageConfig(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "test-image-config"
+	out, _ := dockerCmd(c, "image", "config", "-t", name, "--env", "GREETING=hello world", "--label", "com.example.ci=1", "--expose", "8080", "--cmd", `["echo", "configured"]`, "busybox")
+	id := strings.TrimSpace(out)
+	c.Assert(inspectField(c, name, "Id"), checker.Equals, id)
+
+	c.Assert(inspectFieldJSON(c, name, "Config.Env"), checker.Contains, `"GREETING=hello world"`)
+	c.Assert(inspectFieldJSON(c, name, "Config.Labels"), checker.Contains, `"com.example.ci":"1"`)
+	c.Assert(inspectFieldJSON(c, name, "Config.ExposedPorts"), checker.Contains, `"8080/tcp"`)
+	c.Assert(inspectFieldJSON(c, name, "Config.Cmd"), checker.Equals, `["echo","configured"]`)
+
+	// The image shares the layers of busybox
+	c.Assert(inspectFieldJSON(c, name, "RootFS.Layers"), checker.Equals, inspectFieldJSON(c, "busybox", "RootFS.Layers"))
+
+	out, _ = dockerCmd(c, "run", "--rm", name)
+	c.Assert(strings.TrimSpace(out), checker.Equals, "configured")
+	out, _ = dockerCmd(c, "run", "--rm", name, "sh", "-c", "echo $GREETING")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "hello world")
+}
+
+func (s *DockerSuite) TestImageConfigInvalid(c *check.C) {
+	out, _, err := dockerCmdWithError("image", "config", "-c", "RUN true", "busybox")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "run is not a valid change command")
+
+	out, _, err = dockerCmdWithError("image", "config", "--env", "A=b", "test-image-config-missing")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "No such image")
+}
@@ -0,0 +1,15 @@
+package main
+
+import (
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/go-check/check"
+)
+
+func (s *DockerSuite) TestImageVerify(c *check.C) {
+	out, _ := dockerCmd(c, "image", "verify", "busybox")
+	c.Assert(out, checker.Contains, "none is corrupt")
+
+	out, _, err := dockerCmdWithError("image", "verify", "test-image-verify-missing")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "No such image")
+}
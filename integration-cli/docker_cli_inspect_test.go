@@ -391,3 +391,25 @@ func (s *DockerSuite) TestInspectRootFS(c *check.C) {
 
 	c.Assert(len(imageJSON[0].RootFS.Layers), checker.GreaterOrEqualThan, 1)
 }
+
+func (s *DockerSuite) TestInspectUsageAfterExit(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	since := daemonUnixTime(c)
+	// The usage is recorded even for a container which exits right away.
+	dockerCmd(c, "run", "--name", "usage", "busybox", "dd", "if=/dev/zero", "of=/dev/null", "bs=1M", "count=100")
+
+	var usage types.ContainerUsage
+	inspectFieldAndMarshall(c, "usage", "State.Usage", &usage)
+	c.Assert(usage.CPUTime, checker.GreaterThan, uint64(0))
+	c.Assert(usage.MemoryMaxUsage, checker.GreaterThan, uint64(0))
+
+	out, _ := dockerCmd(c, "events", "--filter", "container=usage", "--filter", "event=die", "--since", since, "--until", daemonUnixTime(c))
+	c.Assert(out, checker.Contains, fmt.Sprintf("cpuTime=%d", usage.CPUTime))
+	c.Assert(out, checker.Contains, fmt.Sprintf("memoryMaxUsage=%d", usage.MemoryMaxUsage))
+
+	// The usage of the last run is gone once the container is started again.
+	dockerCmd(c, "start", "usage")
+	c.Assert(waitRun("usage"), checker.IsNil)
+	out = inspectFieldJSON(c, "usage", "State.Usage")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "null")
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/docker/pkg/integration/checker"
+	"github.com/go-check/check"
+)
+
+func (s *DockerRegistrySuite) TestManifestCreateAnnotatePush(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	repoName := fmt.Sprintf("%v/dockercli/multiarch", privateRegistryURL)
+	otherRepoName := fmt.Sprintf("%v/dockercli/busybox-arm", privateRegistryURL)
+
+	dockerCmd(c, "tag", "busybox", repoName+":amd64")
+	dockerCmd(c, "push", repoName+":amd64")
+	dockerCmd(c, "tag", "busybox", otherRepoName+":latest")
+	dockerCmd(c, "push", otherRepoName+":latest")
+
+	out, _ := dockerCmd(c, "manifest", "create", repoName+":latest", repoName+":amd64", otherRepoName)
+	c.Assert(out, checker.Contains, "Created manifest list "+repoName+":latest")
+
+	out, _, err := dockerCmdWithError("manifest", "create", repoName+":latest", repoName+":amd64")
+	c.Assert(err, checker.NotNil, check.Commentf("expected creating an existing manifest list to fail: %s", out))
+	c.Assert(out, checker.Contains, "--amend")
+
+	// busybox is built for the daemon platform, so record the second
+	// manifest as an arm image to select it on pull.
+	dockerCmd(c, "manifest", "annotate", "--arch", "arm", "--variant", "v7", repoName+":latest", otherRepoName+":latest")
+
+	out, _ = dockerCmd(c, "manifest", "push", "--purge", repoName+":latest")
+	c.Assert(out, checker.Contains, "latest: digest: sha256:")
+
+	out, _, err = dockerCmdWithError("manifest", "push", repoName+":latest")
+	c.Assert(err, checker.NotNil, check.Commentf("expected the purged manifest list to be removed: %s", out))
+	c.Assert(out, checker.Contains, "No such manifest list")
+
+	deleteImages(repoName+":amd64", otherRepoName+":latest")
+	out, _ = dockerCmd(c, "pull", repoName+":latest")
+	c.Assert(strings.TrimSpace(out), checker.Contains, "Digest: sha256:")
+
+	// The manifest of the other repository was copied to the repository of
+	// the manifest list, so it is found, but its image configuration doesn't
+	// match the platform it was annotated with.
+	out, _, err = dockerCmdWithError("pull", "--platform", "linux/arm/v7", repoName+":latest")
+	c.Assert(err, checker.NotNil, check.Commentf("expected the annotated platform not to match the image: %s", out))
+	c.Assert(out, checker.Contains, "not the requested platform linux/arm/v7")
+}
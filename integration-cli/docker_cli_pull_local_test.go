@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -421,3 +422,36 @@ func (s *DockerRegistryAuthHtpasswdSuite) TestPullWithExternalAuth(c *check.C) {
 
 	dockerCmd(c, "--config", tmp, "pull", repoName)
 }
+
+// TestPullPushMaxBandwidth pushes and pulls an image with a bandwidth limit,
+// and checks the pull progress shows the speed it is throttled to.
+func (s *DockerRegistrySuite) TestPullPushMaxBandwidth(c *check.C) {
+	repoName := fmt.Sprintf("%v/dockercli/busybox", privateRegistryURL)
+	dockerCmd(c, "tag", "busybox", repoName)
+	dockerCmd(c, "push", "--max-bandwidth", "10MB", repoName)
+	dockerCmd(c, "rmi", repoName)
+
+	res, body, err := sockRequestRaw("POST", "/images/create?fromImage="+repoName+"&tag=latest&maxbandwidth=1000000", nil, "")
+	c.Assert(err, checker.IsNil)
+	c.Assert(res.StatusCode, checker.Equals, http.StatusOK)
+	b, err := readBody(body)
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(b), checker.Contains, "Downloading (throttled to 1 MB/s)")
+
+	dockerCmd(c, "inspect", repoName)
+}
+
+// TestPullPushMaxBandwidthInvalid checks that pulls and pushes with an
+// invalid bandwidth limit are rejected.
+func (s *DockerSuite) TestPullPushMaxBandwidthInvalid(c *check.C) {
+	for _, cmd := range []string{"pull", "push"} {
+		out, _, err := dockerCmdWithError(cmd, "--max-bandwidth", "fast", "127.0.0.1:5000/busybox")
+		c.Assert(err, checker.NotNil, check.Commentf("expected an invalid bandwidth to fail: %s", out))
+		c.Assert(out, checker.Contains, "invalid --max-bandwidth")
+	}
+
+	res, body, err := sockRequestRaw("POST", "/images/create?fromImage=busybox&tag=latest&maxbandwidth=-1", nil, "")
+	c.Assert(err, checker.IsNil)
+	body.Close()
+	c.Assert(res.StatusCode, checker.Equals, http.StatusInternalServerError)
+}
@@ -272,3 +272,28 @@ func (s *DockerRegistryAuthHtpasswdSuite) TestPullNoCredentialsNotFound(c *check
 	c.Assert(err, check.NotNil, check.Commentf(out))
 	c.Assert(out, checker.Contains, "Error: image busybox:latest not found")
 }
+
+// TestPullPlatform pulls the image for another platform from a manifest list
+// and checks the platform is recorded in the image.
+func (s *DockerHubPullSuite) TestPullPlatform(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	out := s.Cmd(c, "pull", "--platform", "linux/arm64", "busybox:latest")
+	defer deleteImages("busybox:latest")
+	c.Assert(out, checker.Contains, "Downloaded newer image for busybox:latest")
+
+	out = s.Cmd(c, "inspect", "--format", "{{.Os}}/{{.Architecture}}", "busybox:latest")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "linux/arm64")
+}
+
+// TestPullPlatformInvalid checks that pulling with an invalid or unavailable
+// platform fails without pulling anything.
+func (s *DockerHubPullSuite) TestPullPlatformInvalid(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	out, err := s.CmdWithError("pull", "--platform", "linux", "busybox:latest")
+	c.Assert(err, checker.NotNil, check.Commentf("expected an invalid platform to fail: %s", out))
+	c.Assert(out, checker.Contains, "must be in the form os/arch[/variant]")
+
+	out, err = s.CmdWithError("pull", "--platform", "plan9/amd64", "busybox:latest")
+	c.Assert(err, checker.NotNil, check.Commentf("expected an unavailable platform to fail: %s", out))
+	c.Assert(out, checker.Contains, "no matching manifest for platform plan9/amd64")
+}
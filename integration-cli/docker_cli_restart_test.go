@@ -247,3 +247,26 @@ func (s *DockerSuite) TestRestartContainerwithRestartPolicy(c *check.C) {
 	dockerCmd(c, "start", id1)
 	dockerCmd(c, "start", id2)
 }
+
+func (s *DockerSuite) TestRestartPolicyWindow(c *check.C) {
+	out, _ := dockerCmd(c, "run", "-d", "--restart=always", "--restart-max-delay=200ms", "--restart-window=1h", "--restart-window-max=3", "busybox", "false")
+	id := strings.TrimSpace(out)
+
+	// the container isn't restarted once it's been restarted 3 times in the window
+	err := waitInspect(id, "{{ .State.Restarting }} {{ .State.Running }} {{ .RestartCount }}", "false false 3", 30*time.Second)
+	c.Assert(err, checker.IsNil)
+
+	out = inspectFieldJSON(c, id, "State.RestartBackoff")
+	c.Assert(out, checker.Contains, `"Delay":200000000`)
+	c.Assert(out, checker.Contains, `"RestartsInWindow":3`)
+}
+
+func (s *DockerSuite) TestRestartPolicyWindowInvalid(c *check.C) {
+	out, _, err := dockerCmdWithError("run", "-d", "--restart=always", "--restart-window=1h", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Restart window and maximum restarts in window must be set together")
+
+	out, _, err = dockerCmdWithError("run", "-d", "--restart-max-delay=1s", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, `Restart policy options are not valid with restart policy of "no"`)
+}
@@ -7,6 +7,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -22,6 +23,8 @@ import (
 	"github.com/docker/docker/pkg/mount"
 	"github.com/docker/docker/pkg/parsers"
 	"github.com/docker/docker/pkg/sysinfo"
+	"github.com/docker/engine-api/types"
+	mounttypes "github.com/docker/engine-api/types/mount"
 	"github.com/go-check/check"
 	"github.com/kr/pty"
 )
@@ -884,6 +887,40 @@ func (s *DockerSuite) TestRunSeccompProfileDenyCloneUserns(c *check.C) {
 	}
 }
 
+// TestRunSeccompAuditProfile checks that 'docker run --security-opt seccomp=audit'
+// learns a profile allowing the syscalls the container made.
+func (s *DockerSuite) TestRunSeccompAuditProfile(c *check.C) {
+	testRequires(c, SameHostDaemon, seccompEnabled, seccompAuditSupported)
+
+	dockerCmd(c, "run", "--name", "audited", "--security-opt", "seccomp=audit", "busybox", "chmod", "400", "/etc/hostname")
+
+	status, body, err := sockRequest("GET", "/containers/audited/seccomp", nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusOK, check.Commentf(string(body)))
+
+	var profile types.Seccomp
+	c.Assert(json.Unmarshal(body, &profile), checker.IsNil)
+	c.Assert(profile.DefaultAction, checker.Equals, types.ActErrno)
+	learned := map[string]bool{}
+	for _, s := range profile.Syscalls {
+		learned[s.Name] = true
+	}
+	c.Assert(learned["chmod"] || learned["fchmodat"], checker.True, check.Commentf("chmod not learned: %s", body))
+
+	dockerCmd(c, "run", "--name", "notaudited", "--security-opt", "seccomp=unconfined", "busybox", "true")
+	status, _, err = sockRequest("GET", "/containers/notaudited/seccomp", nil)
+	c.Assert(err, checker.IsNil)
+	c.Assert(status, checker.Equals, http.StatusBadRequest)
+}
+
+func (s *DockerSuite) TestRunSeccompAuditNotSupported(c *check.C) {
+	testRequires(c, SameHostDaemon, seccompEnabled, seccompAuditNotSupported)
+
+	out, _, err := dockerCmdWithError("run", "--security-opt", "seccomp=audit", "busybox", "true")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Matches, `(?s).*(kernel doesn't support the log action of seccomp|doesn't support the SCMP_ACT_LOG seccomp action).*`)
+}
+
 // TestRunSeccompUnconfinedCloneUserns checks that
 // 'docker run --security-opt seccomp=unconfined syscall-test' allows creating a userns.
 func (s *DockerSuite) TestRunSeccompUnconfinedCloneUserns(c *check.C) {
@@ -1076,3 +1113,103 @@ func (s *DockerSuite) TestRunUserDeviceAllowed(c *check.C) {
 	out, _ := dockerCmd(c, "run", "--device", "/dev/snd/timer:w", "busybox", "cat", file)
 	c.Assert(out, checker.Contains, fmt.Sprintf("c %d:%d w", stat.Rdev/256, stat.Rdev%256))
 }
+
+func (s *DockerSuite) TestRunWithInit(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	// The init runs as PID 1, with the container command as its child
+	out, _ := dockerCmd(c, "run", "--rm", "--init", "busybox", "cat", "/proc/1/cmdline")
+	c.Assert(strings.Replace(out, "\x00", " ", -1), checker.Contains, "/dev/init -- cat /proc/1/cmdline")
+
+	// SIGTERM is forwarded to a command which doesn't handle it on its own
+	out, _ = dockerCmd(c, "run", "-d", "--init", "busybox", "top")
+	id := strings.TrimSpace(out)
+	dockerCmd(c, "stop", "-t", "10", id)
+	c.Assert(inspectField(c, id, "State.ExitCode"), checker.Equals, "143")
+
+	out, _ = dockerCmd(c, "run", "--rm", "--init=false", "busybox", "cat", "/proc/1/cmdline")
+	c.Assert(out, checker.Not(checker.Contains), "/dev/init")
+}
+
+func (s *DockerSuite) TestRunMountBind(c *check.C) {
+	testRequires(c, DaemonIsLinux, SameHostDaemon)
+
+	tmpDir, err := ioutil.TempDir("", "mount-bind")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+	c.Assert(ioutil.WriteFile(filepath.Join(tmpDir, "file"), []byte("hello"), 0644), checker.IsNil)
+
+	out, _ := dockerCmd(c, "run", "--name", "test-mount-bind", "--mount", "type=bind,source="+tmpDir+",target=/foo,readonly", "busybox", "cat", "/foo/file")
+	c.Assert(out, checker.Equals, "hello")
+
+	var mounts []types.MountPoint
+	c.Assert(json.Unmarshal([]byte(inspectFieldJSON(c, "test-mount-bind", "Mounts")), &mounts), checker.IsNil)
+	c.Assert(mounts, checker.HasLen, 1)
+	c.Assert(mounts[0].Type, checker.Equals, mounttypes.TypeBind)
+	c.Assert(mounts[0].Source, checker.Equals, tmpDir)
+	c.Assert(mounts[0].Destination, checker.Equals, "/foo")
+	c.Assert(mounts[0].RW, checker.False)
+
+	out, _, err = dockerCmdWithError("run", "--mount", "type=bind,source="+tmpDir+",target=/foo,readonly", "busybox", "touch", "/foo/file")
+	c.Assert(err, checker.NotNil, check.Commentf(out))
+	c.Assert(out, checker.Contains, "Read-only file system")
+
+	// Bind mounts set with -v have the same type
+	dockerCmd(c, "create", "--name", "test-volume-bind", "-v", tmpDir+":/foo", "busybox")
+	mounts = nil
+	c.Assert(json.Unmarshal([]byte(inspectFieldJSON(c, "test-volume-bind", "Mounts")), &mounts), checker.IsNil)
+	c.Assert(mounts, checker.HasLen, 1)
+	c.Assert(mounts[0].Type, checker.Equals, mounttypes.TypeBind)
+}
+
+func (s *DockerSuite) TestRunMountVolume(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	dockerCmd(c, "run", "--name", "test-mount-volume", "--mount", "type=volume,source=mountvolume,target=/foo,volume-label=color=red", "busybox", "touch", "/foo/file")
+	out, _ := dockerCmd(c, "volume", "inspect", "--format", "{{ .Labels.color }}", "mountvolume")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "red")
+
+	// The volume is kept when the container is removed with its volumes
+	dockerCmd(c, "rm", "-v", "test-mount-volume")
+	dockerCmd(c, "run", "--rm", "--mount", "type=volume,source=mountvolume,target=/bar", "busybox", "ls", "/bar/file")
+
+	// The content of the image isn't copied in the volume with volume-nocopy
+	out, _ = dockerCmd(c, "run", "--rm", "--mount", "type=volume,target=/var,volume-nocopy", "busybox", "ls", "/var")
+	c.Assert(strings.TrimSpace(out), checker.Equals, "")
+}
+
+func (s *DockerSuite) TestRunMountTmpfs(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	out, _ := dockerCmd(c, "run", "--rm", "--mount", "type=tmpfs,target=/foo,tmpfs-size=1m,tmpfs-mode=700", "busybox", "grep", "/foo", "/proc/mounts")
+	c.Assert(out, checker.Contains, "tmpfs /foo tmpfs")
+	c.Assert(out, checker.Contains, "size=1024k")
+	c.Assert(out, checker.Contains, "mode=700")
+}
+
+func (s *DockerSuite) TestRunMountInvalid(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	for spec, expected := range map[string]string{
+		"type=bind,source=/does/not/exist,target=/foo":    "bind source path does not exist",
+		"type=bind,target=/foo":                           "field Source must not be empty",
+		"type=tmpfs,source=foo,target=/foo":               "field Source must not be specified",
+		"type=volume,source=/tmp,target=/foo":             "use a bind mount instead",
+		"type=invalid,target=/foo":                        "mount type unknown",
+		"type=volume,target=/foo,bind-propagation=rslave": "cannot mix 'bind-*' options with mount type 'volume'",
+	} {
+		out, _, err := dockerCmdWithError("create", "--mount", spec, "busybox")
+		c.Assert(err, checker.NotNil, check.Commentf(out))
+		c.Assert(out, checker.Contains, expected)
+	}
+
+	for _, args := range [][]string{
+		{"-v", "/foo", "--mount", "type=volume,target=/foo"},
+		{"--tmpfs", "/foo", "--mount", "type=volume,target=/foo"},
+		{"--tmpfs", "/foo", "-v", "/tmp:/foo"},
+	} {
+		out, _, err := dockerCmdWithError(append(append([]string{"create"}, args...), "busybox")...)
+		c.Assert(err, checker.NotNil, check.Commentf(out))
+		c.Assert(out, checker.Contains, "Duplicate mount point '/foo'")
+	}
+}
@@ -350,3 +350,110 @@ func (s *DockerSuite) TestSaveLoadParents(c *check.C) {
 	inspectOut = inspectField(c, idFoo, "Parent")
 	c.Assert(inspectOut, checker.Equals, "")
 }
+
+// TestSaveLoadOCILayout saves an image as an OCI image layout, checks the
+// index refers to the image by its configuration digest, and loads it back.
+func (s *DockerSuite) TestSaveLoadOCILayout(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	repoName := "foobar-save-load-oci:v1"
+	dockerCmd(c, "tag", "busybox:latest", repoName)
+	defer deleteImages(repoName)
+	imageID := inspectField(c, repoName, "Id")
+
+	tmpDir, err := ioutil.TempDir("", "save-load-oci")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+	archivePath := filepath.Join(tmpDir, "image.tar")
+	extractionDirectory := filepath.Join(tmpDir, "layout")
+	c.Assert(os.Mkdir(extractionDirectory, 0755), checker.IsNil)
+
+	dockerCmd(c, "save", "--format", "oci", "-o", archivePath, repoName)
+	out, _, err := runCommandWithOutput(exec.Command("tar", "-xf", archivePath, "-C", extractionDirectory))
+	c.Assert(err, checker.IsNil, check.Commentf("failed to extract the image layout: %s", out))
+
+	layout, err := ioutil.ReadFile(filepath.Join(extractionDirectory, "oci-layout"))
+	c.Assert(err, checker.IsNil)
+	c.Assert(string(layout), checker.Contains, `"imageLayoutVersion":"1.0.0"`)
+
+	var index struct {
+		Manifests []struct {
+			Digest      digest.Digest
+			Annotations map[string]string
+		}
+	}
+	indexJSON, err := ioutil.ReadFile(filepath.Join(extractionDirectory, "index.json"))
+	c.Assert(err, checker.IsNil)
+	c.Assert(json.Unmarshal(indexJSON, &index), checker.IsNil)
+	c.Assert(index.Manifests, checker.HasLen, 1)
+	c.Assert(index.Manifests[0].Annotations["org.opencontainers.image.ref.name"], checker.Equals, "v1")
+	c.Assert(index.Manifests[0].Annotations["io.containerd.image.name"], checker.Equals, repoName)
+
+	var manifest struct {
+		Config struct {
+			Digest digest.Digest
+		}
+	}
+	manifestJSON, err := ioutil.ReadFile(filepath.Join(extractionDirectory, "blobs", "sha256", index.Manifests[0].Digest.Hex()))
+	c.Assert(err, checker.IsNil)
+	c.Assert(json.Unmarshal(manifestJSON, &manifest), checker.IsNil)
+	c.Assert(manifest.Config.Digest.String(), checker.Equals, imageID)
+
+	deleteImages(repoName)
+	dockerCmd(c, "load", "-i", archivePath)
+	c.Assert(inspectField(c, repoName, "Id"), checker.Equals, imageID)
+}
+
+func (s *DockerSuite) TestSaveInvalidFormat(c *check.C) {
+	out, _, err := dockerCmdWithError("save", "--format", "zip", "-o", "/dev/null", "busybox")
+	c.Assert(err, checker.NotNil, check.Commentf("expected an invalid format to fail: %s", out))
+	c.Assert(out, checker.Contains, `invalid format "zip"`)
+}
+
+// TestSaveExcludeLayersOf saves an image without the layers of its base
+// image, and checks it only loads while those layers are present.
+func (s *DockerSuite) TestSaveExcludeLayersOf(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+	name := "test-save-exclude-layers-of"
+	_, err := buildImage(name,
+		`FROM busybox
+		RUN echo excluded > /file`,
+		true)
+	c.Assert(err, checker.IsNil)
+	defer deleteImages(name)
+	imageID := inspectField(c, name, "Id")
+
+	tmpDir, err := ioutil.TempDir("", "save-exclude-layers-of")
+	c.Assert(err, checker.IsNil)
+	defer os.RemoveAll(tmpDir)
+
+	for _, format := range []string{"docker", "oci"} {
+		archivePath := filepath.Join(tmpDir, format+".tar")
+		fullPath := filepath.Join(tmpDir, format+"-full.tar")
+		dockerCmd(c, "save", "--format", format, "--exclude-layers-of", "busybox", "-o", archivePath, name)
+		dockerCmd(c, "save", "--format", format, "-o", fullPath, name)
+
+		archive, err := os.Stat(archivePath)
+		c.Assert(err, checker.IsNil)
+		full, err := os.Stat(fullPath)
+		c.Assert(err, checker.IsNil)
+		c.Assert(archive.Size(), checker.LessThan, full.Size(), check.Commentf("expected the layers of busybox to be left out of the %s archive", format))
+
+		deleteImages(name)
+		dockerCmd(c, "load", "-i", archivePath)
+		c.Assert(inspectField(c, name, "Id"), checker.Equals, imageID)
+	}
+
+	// Layers of the image itself aren't present once it is removed.
+	archivePath := filepath.Join(tmpDir, "self.tar")
+	dockerCmd(c, "save", "--exclude-layers-of", name, "-o", archivePath, name)
+	deleteImages(name)
+	out, _, err := dockerCmdWithError("load", "-i", archivePath)
+	c.Assert(err, checker.NotNil, check.Commentf("expected loading without the excluded layers to fail: %s", out))
+	c.Assert(out, checker.Contains, "was excluded from the archive and is not present")
+}
+
+func (s *DockerSuite) TestSaveExcludeLayersOfInvalid(c *check.C) {
+	out, _, err := dockerCmdWithError("save", "--exclude-layers-of", "notanimage", "-o", "/dev/null", "busybox")
+	c.Assert(err, checker.NotNil, check.Commentf("expected an invalid exclusion to fail: %s", out))
+	c.Assert(out, checker.Contains, `invalid layer exclusion "notanimage"`)
+}
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"time"
+
 	"github.com/docker/docker/pkg/integration/checker"
 	"github.com/go-check/check"
 )
@@ -15,3 +17,30 @@ func (s *DockerSuite) TestStopContainerWithRestartPolicyAlways(c *check.C) {
 	dockerCmd(c, "stop", "verifyRestart1")
 	dockerCmd(c, "stop", "verifyRestart2")
 }
+
+func (s *DockerSuite) TestStopContainerWithStopTimeout(c *check.C) {
+	testRequires(c, DaemonIsLinux)
+
+	// the shell ignores SIGTERM as PID 1, it's only stopped by SIGKILL
+	dockerCmd(c, "run", "--name", "stoptimeout", "-d", "--stop-timeout=1", "busybox", "sh", "-c", "while true; do sleep 1; done")
+	c.Assert(waitRun("stoptimeout"), checker.IsNil)
+	c.Assert(inspectField(c, "stoptimeout", "Config.StopTimeout"), checker.Equals, "1")
+
+	start := time.Now()
+	dockerCmd(c, "stop", "stoptimeout")
+	c.Assert(time.Since(start) < 8*time.Second, checker.True, check.Commentf("the stop timeout of the container was not used"))
+
+	dockerCmd(c, "start", "stoptimeout")
+	c.Assert(waitRun("stoptimeout"), checker.IsNil)
+
+	// --time overrides the stop timeout of the container
+	start = time.Now()
+	dockerCmd(c, "restart", "--time=4", "stoptimeout")
+	c.Assert(time.Since(start) >= 4*time.Second, checker.True, check.Commentf("the timeout given to restart was not used"))
+}
+
+func (s *DockerSuite) TestRunStopTimeoutInvalid(c *check.C) {
+	out, _, err := dockerCmdWithError("run", "--stop-timeout=-1", "busybox", "true")
+	c.Assert(err, checker.NotNil)
+	c.Assert(out, checker.Contains, "Invalid stop timeout -1: it must not be negative")
+}
@@ -29,3 +29,24 @@ func (s *DockerSuite) TestUpdateRestartPolicy(c *check.C) {
 	maximumRetryCount := inspectField(c, id, "HostConfig.RestartPolicy.MaximumRetryCount")
 	c.Assert(maximumRetryCount, checker.Equals, "5")
 }
+
+func (s *DockerSuite) TestUpdateRestartPolicyBackoff(c *check.C) {
+	out, _ := dockerCmd(c, "run", "-d", "--restart=always", "--restart-max-delay=1m", "busybox", "sh", "-c", "sleep 1 && false")
+	id := strings.TrimSpace(string(out))
+
+	// the backoff options are updated without changing the restart policy
+	dockerCmd(c, "update", "--restart-window=1h", "--restart-window-max=2", id)
+
+	err := waitInspect(id, "{{ .State.Restarting }} {{ .State.Running }} {{ .RestartCount }}", "false false 2", 60*time.Second)
+	c.Assert(err, checker.IsNil)
+
+	c.Assert(inspectField(c, id, "HostConfig.RestartPolicy.Name"), checker.Equals, "always")
+	c.Assert(inspectField(c, id, "HostConfig.RestartPolicy.MaxDelay"), checker.Equals, "60000000000")
+	c.Assert(inspectField(c, id, "HostConfig.RestartPolicy.MaxRestartsInWindow"), checker.Equals, "2")
+
+	// options are unset by setting them to 0
+	dockerCmd(c, "update", "--restart-max-delay=0", id)
+	c.Assert(inspectField(c, id, "HostConfig.RestartPolicy.Name"), checker.Equals, "always")
+	c.Assert(inspectField(c, id, "HostConfig.RestartPolicy.MaxDelay"), checker.Equals, "0")
+	c.Assert(inspectField(c, id, "HostConfig.RestartPolicy.MaxRestartsInWindow"), checker.Equals, "2")
+}
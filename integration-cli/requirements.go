@@ -112,6 +112,14 @@ var (
 		},
 		fmt.Sprintf("Test requires an environment that can host %s in the same host", notaryServerBinary),
 	}
+	Criu = testRequirement{
+		func() bool {
+			// the daemon is expected to be on the same host, see SameHostDaemon
+			_, err := exec.LookPath("criu")
+			return err == nil
+		},
+		"Test requires CRIU to be installed on the host.",
+	}
 	NotOverlay = testRequirement{
 		func() bool {
 			return !strings.HasPrefix(daemonStorageDriver, "overlay")
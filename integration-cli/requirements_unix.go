@@ -3,6 +3,11 @@
 package main
 
 import (
+	"bytes"
+	"io/ioutil"
+	"os/exec"
+	"strings"
+
 	"github.com/docker/docker/pkg/sysinfo"
 )
 
@@ -87,6 +92,18 @@ var (
 		},
 		"Test requires that seccomp support be enabled in the daemon.",
 	}
+	seccompAuditSupported = testRequirement{
+		func() bool {
+			return supportsSeccompAudit()
+		},
+		"Test requires a kernel and a runtime supporting the log action of seccomp.",
+	}
+	seccompAuditNotSupported = testRequirement{
+		func() bool {
+			return !supportsSeccompAudit()
+		},
+		"Test requires a kernel or a runtime not supporting the log action of seccomp.",
+	}
 	bridgeNfIptables = testRequirement{
 		func() bool {
 			return !SysInfo.BridgeNFCallIPTablesDisabled
@@ -104,3 +121,18 @@ var (
 func init() {
 	SysInfo = sysinfo.New(true)
 }
+
+// supportsSeccompAudit returns whether the kernel and the default runtime
+// support the log action of seccomp audit mode, the way the daemon checks it.
+func supportsSeccompAudit() bool {
+	actions, err := ioutil.ReadFile("/proc/sys/kernel/seccomp/actions_avail")
+	if err != nil || !strings.Contains(" "+strings.TrimSpace(string(actions))+" ", " log ") {
+		return false
+	}
+	runtime, err := exec.LookPath("docker-runc")
+	if err != nil {
+		return false
+	}
+	b, err := ioutil.ReadFile(runtime)
+	return err == nil && bytes.Contains(b, []byte("SCMP_ACT_LOG"))
+}
@@ -0,0 +1,29 @@
+package layer
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// Verify checks the integrity of l. The tar stream of l is rebuilt from the
+// graph driver and the tar-split metadata of l, and its digest compared with
+// the DiffID of l. The ChainID of l must be derived from the ChainID of its
+// parent and its DiffID.
+func Verify(l Layer) error {
+	var parent ChainID
+	if p := l.Parent(); p != nil {
+		parent = p.ChainID()
+	}
+	if expected := createChainIDFromParent(parent, l.DiffID()); l.ChainID() != expected {
+		return fmt.Errorf("invalid chain ID %s, expected %s", l.ChainID(), expected)
+	}
+
+	ts, err := l.TarStream()
+	if err != nil {
+		return err
+	}
+	defer ts.Close()
+	_, err = io.Copy(ioutil.Discard, ts)
+	return err
+}
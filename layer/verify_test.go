@@ -0,0 +1,61 @@
+package layer
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	// TODO Windows: Figure out why TarStream verification is failing
+	if runtime.GOOS == "windows" {
+		t.Skip("Failing on Windows")
+	}
+	ls, _, cleanup := newTestStore(t)
+	defer cleanup()
+
+	layer1, err := createLayer(ls, "", initWithFiles(newTestFile("/etc/hosts", []byte("localhost"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	layer2, err := createLayer(ls, layer1.ChainID(), initWithFiles(newTestFile("/etc/hostname", []byte("layer2"), 0644)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, l := range []Layer{layer1, layer2} {
+		if err := Verify(l); err != nil {
+			t.Fatalf("Expected layer %s to be valid, got %v", l.ChainID(), err)
+		}
+	}
+
+	// Change the content of a file of the top layer in the graph driver.
+	driver := ls.(*layerStore).driver
+	root, err := driver.Get(cacheID(layer2), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(root, "etc", "hostname"), []byte("corrupt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := driver.Put(cacheID(layer2)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Verify(layer2); err == nil {
+		t.Fatal("Expected a layer with a changed file to be corrupt")
+	}
+	if err := Verify(layer1); err != nil {
+		t.Fatalf("Expected the parent layer to be valid, got %v", err)
+	}
+
+	// A chain ID not derived from the parent and DiffID is invalid.
+	chainID := layer1.ChainID()
+	getCachedLayer(layer1).chainID = layer2.ChainID()
+	err = Verify(layer1)
+	getCachedLayer(layer1).chainID = chainID
+	if err == nil {
+		t.Fatal("Expected a layer with a wrong chain ID to be corrupt")
+	}
+}
@@ -361,6 +361,25 @@ func (clnt *client) Signal(containerID string, sig int) error {
 	return nil
 }
 
+// SignalProcess handles a signal sent to an exec. Windows processes can't be
+// sent arbitrary signals, so the process is terminated whatever the signal.
+func (clnt *client) SignalProcess(containerID string, processFriendlyName string, sig int) error {
+	clnt.lock(containerID)
+	defer clnt.unlock(containerID)
+	cont, err := clnt.getContainer(containerID)
+	if err != nil {
+		return err
+	}
+
+	p, ok := cont.processes[processFriendlyName]
+	if !ok {
+		return fmt.Errorf("SignalProcess: unknown process %s in container %s", processFriendlyName, containerID)
+	}
+
+	logrus.Debugf("lcd: SignalProcess() containerID=%s process=%s sig=%d pid=%d", containerID, processFriendlyName, sig, p.systemPid)
+	return hcsshim.TerminateProcessInComputeSystem(containerID, p.systemPid)
+}
+
 // Resize handles a CLI event to resize an interactive docker run or docker exec
 // window.
 func (clnt *client) Resize(containerID, processFriendlyName string, width, height int) error {
@@ -469,3 +488,13 @@ func (clnt *client) UpdateResources(containerID string, resources Resources) err
 	// but we should return nil for enabling updating container
 	return nil
 }
+
+// CreateCheckpoint is not supported on Windows.
+func (clnt *client) CreateCheckpoint(containerID string, checkpointID string, checkpointDir string, exit bool) error {
+	return errors.New("Windows: Containers do not support checkpoints")
+}
+
+// DeleteCheckpoint is not supported on Windows.
+func (clnt *client) DeleteCheckpoint(containerID string, checkpointID string, checkpointDir string) error {
+	return errors.New("Windows: Containers do not support checkpoints")
+}
@@ -2,10 +2,12 @@ package libcontainerd
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	"github.com/Sirupsen/logrus"
 	containerd "github.com/docker/containerd/api/grpc/types"
@@ -18,7 +20,51 @@ type container struct {
 
 	// Platform specific fields are below here.
 	pauseMonitor
-	oom bool
+	oom           bool
+	runtime       string
+	runtimeArgs   []string
+	checkpoint    string
+	checkpointDir string
+}
+
+// WithRuntime sets the OCI runtime containerd runs the container with,
+// instead of its default one.
+func WithRuntime(path string, args []string) CreateOption {
+	return runtime{path, args}
+}
+
+type runtime struct {
+	path string
+	args []string
+}
+
+func (rt runtime) Apply(p interface{}) error {
+	if pr, ok := p.(*container); ok {
+		pr.runtime = rt.path
+		pr.runtimeArgs = rt.args
+		return nil
+	}
+	return fmt.Errorf("WithRuntime option not supported for this client")
+}
+
+// WithCheckpoint restores the container from the given checkpoint, stored in
+// checkpointDir, when it's started.
+func WithCheckpoint(name, checkpointDir string) CreateOption {
+	return checkpoint{name, checkpointDir}
+}
+
+type checkpoint struct {
+	name string
+	dir  string
+}
+
+func (cp checkpoint) Apply(p interface{}) error {
+	if pr, ok := p.(*container); ok {
+		pr.checkpoint = cp.name
+		pr.checkpointDir = cp.dir
+		return nil
+	}
+	return fmt.Errorf("WithCheckpoint option not supported for this client")
 }
 
 func (ctr *container) clean() error {
@@ -65,8 +111,16 @@ func (ctr *container) start() error {
 		Stdout:     ctr.fifo(syscall.Stdout),
 		Stderr:     ctr.fifo(syscall.Stderr),
 		// check to see if we are running in ramdisk to disable pivot root
-		NoPivotRoot: os.Getenv("DOCKER_RAMDISK") != "",
+		NoPivotRoot:   os.Getenv("DOCKER_RAMDISK") != "",
+		Runtime:       ctr.runtime,
+		RuntimeArgs:   ctr.runtimeArgs,
+		Checkpoint:    ctr.checkpoint,
+		CheckpointDir: ctr.checkpointDir,
 	}
+	// The checkpoint is only restored on the first start, restarts of the
+	// container by its restart policy start it afresh.
+	ctr.checkpoint, ctr.checkpointDir = "", ""
+	ctr.startedAt = time.Now()
 	ctr.client.appendContainer(ctr)
 
 	resp, err := ctr.client.remote.apiClient.CreateContainer(context.Background(), r)
@@ -75,6 +129,18 @@ func (ctr *container) start() error {
 		return err
 	}
 
+	// containerd versions that don't support choosing the runtime of a
+	// container run it with their own runtime instead.
+	if ctr.runtime != "" && resp.Container.Runtime != ctr.runtime {
+		ctr.client.remote.apiClient.Signal(context.Background(), &containerd.SignalRequest{
+			Id:     ctr.containerID,
+			Pid:    InitFriendlyName,
+			Signal: uint32(syscall.SIGKILL),
+		})
+		ctr.closeFifos(iopipe)
+		return fmt.Errorf("containerd started the container with runtime %q instead of %q, it doesn't support choosing the runtime of a container", resp.Container.Runtime, ctr.runtime)
+	}
+
 	if err := ctr.client.backend.AttachStreams(ctr.containerID, *iopipe); err != nil {
 		return err
 	}
@@ -118,7 +184,7 @@ func (ctr *container) handleEvent(e *containerd.Event) error {
 			st.State = StateExitProcess
 		}
 		if st.State == StateExit && ctr.restartManager != nil {
-			restart, wait, err := ctr.restartManager.ShouldRestart(e.Status, false)
+			restart, wait, err := ctr.restartManager.ShouldRestart(e.Status, false, time.Since(ctr.startedAt))
 			if err != nil {
 				logrus.Error(err)
 			} else if restart {
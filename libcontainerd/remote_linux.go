@@ -48,6 +48,7 @@ type remote struct {
 	eventTsPath string
 	pastEvents  map[string]*containerd.Event
 	runtimeArgs []string
+	liveRestore bool
 }
 
 // New creates a fresh instance of libcontainerd remote.
@@ -178,6 +179,7 @@ func (r *remote) Client(b Backend) (Client, error) {
 		},
 		remote:        r,
 		exitNotifiers: make(map[string]*exitNotifier),
+		liveRestore:   r.liveRestore,
 	}
 
 	r.Lock()
@@ -186,6 +188,15 @@ func (r *remote) Client(b Backend) (Client, error) {
 	return c, nil
 }
 
+func (r *remote) UpdateOptions(options ...RemoteOption) error {
+	for _, option := range options {
+		if err := option.Apply(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (r *remote) updateEventTimestamp(t time.Time) {
 	f, err := os.OpenFile(r.eventTsPath, syscall.O_CREAT|syscall.O_WRONLY|syscall.O_TRUNC, 0600)
 	defer f.Close()
@@ -439,3 +450,23 @@ func (d debugLog) Apply(r Remote) error {
 	}
 	return fmt.Errorf("WithDebugLog option not supported for this remote")
 }
+
+// WithLiveRestore defines if containers are stopped on shutdown or restored.
+func WithLiveRestore(v bool) RemoteOption {
+	return liveRestore(v)
+}
+
+type liveRestore bool
+
+func (l liveRestore) Apply(r Remote) error {
+	if remote, ok := r.(*remote); ok {
+		remote.Lock()
+		remote.liveRestore = bool(l)
+		for _, c := range remote.clients {
+			c.liveRestore = bool(l)
+		}
+		remote.Unlock()
+		return nil
+	}
+	return fmt.Errorf("WithLiveRestore option not supported for this remote")
+}
@@ -37,6 +37,12 @@ type (
 	TarChownOptions struct {
 		UID, GID int
 	}
+	// TarChmodOptions wraps the chmod options Mode. When unpacking, Mode
+	// replaces the permission bits, including the setuid, setgid and
+	// sticky bits, of every entry except symlinks.
+	TarChmodOptions struct {
+		Mode int64
+	}
 	// TarOptions wraps the tar options.
 	TarOptions struct {
 		IncludeFiles     []string
@@ -46,6 +52,7 @@ type (
 		UIDMaps          []idtools.IDMap
 		GIDMaps          []idtools.IDMap
 		ChownOpts        *TarChownOptions
+		ChmodOpts        *TarChmodOptions
 		IncludeSourceDir bool
 		// When unpacking, specifies whether overwriting a directory with a
 		// non-directory is allowed and vice versa.
@@ -766,6 +773,10 @@ loop:
 			hdr.Gid = xGID
 		}
 
+		if options.ChmodOpts != nil && hdr.Typeflag != tar.TypeSymlink {
+			hdr.Mode = hdr.Mode&^07777 | options.ChmodOpts.Mode&07777
+		}
+
 		if err := createTarFile(path, dest, hdr, trBuf, !options.NoLchown, options.ChownOpts); err != nil {
 			return err
 		}
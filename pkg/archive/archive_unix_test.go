@@ -243,3 +243,53 @@ func TestTarUntarWithXattr(t *testing.T) {
 		}
 	}
 }
+
+func TestUntarWithChmodOpts(t *testing.T) {
+	origin, err := ioutil.TempDir("", "docker-test-untar-chmod-origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(origin)
+	if err := os.Mkdir(filepath.Join(origin, "dir"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(origin, "dir", "file"), []byte("hello"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("file", filepath.Join(origin, "dir", "link")); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := TarWithOptions(origin, &TarOptions{Compression: Uncompressed})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+
+	dest, err := ioutil.TempDir("", "docker-test-untar-chmod-dest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dest)
+
+	if err := Untar(archive, dest, &TarOptions{NoLchown: true, ChmodOpts: &TarChmodOptions{Mode: 0755}}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, p := range []string{"dir", "dir/file"} {
+		fi, err := os.Lstat(filepath.Join(dest, p))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != 0755 {
+			t.Fatalf("expected %s to have mode 0755, got %v", p, fi.Mode().Perm())
+		}
+	}
+	fi, err := os.Lstat(filepath.Join(dest, "dir", "link"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("expected dir/link to be a symlink, got %v", fi.Mode())
+	}
+}
@@ -0,0 +1,79 @@
+package audit
+
+import (
+	"bytes"
+	"syscall"
+)
+
+// nlgrpReadlog is the read-only multicast group of the audit netlink socket,
+// AUDIT_NLGRP_READLOG in linux/audit.h.
+const nlgrpReadlog = 1
+
+// Message is a record of the kernel audit subsystem.
+type Message struct {
+	Type uint16
+	Data string
+}
+
+// Listener receives the records of the kernel audit subsystem through its
+// read-only multicast group. Unlike the audit daemon, any number of listeners
+// can receive the records, so it doesn't get in the way of auditd. It requires
+// Linux 3.16 or later, and the CAP_AUDIT_READ capability.
+type Listener struct {
+	fd  int
+	buf []byte
+}
+
+// NewListener returns a Listener subscribed to the records of the kernel
+// audit subsystem.
+func NewListener() (*Listener, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_RAW|syscall.SOCK_CLOEXEC, syscall.NETLINK_AUDIT)
+	if err != nil {
+		return nil, err
+	}
+	sa := &syscall.SockaddrNetlink{
+		Family: syscall.AF_NETLINK,
+		Groups: 1 << (nlgrpReadlog - 1),
+	}
+	if err := syscall.Bind(fd, sa); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	return &Listener{fd: fd, buf: make([]byte, 64*1024)}, nil
+}
+
+// Receive waits for records and returns them. It returns syscall.ENOBUFS if
+// records were lost because they weren't received fast enough.
+func (l *Listener) Receive() ([]Message, error) {
+	var (
+		n   int
+		err error
+	)
+	for {
+		n, _, err = syscall.Recvfrom(l.fd, l.buf, 0)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := syscall.ParseNetlinkMessage(l.buf[:n])
+	if err != nil {
+		return nil, err
+	}
+	records := make([]Message, 0, len(msgs))
+	for _, m := range msgs {
+		records = append(records, Message{
+			Type: m.Header.Type,
+			Data: string(bytes.TrimRight(m.Data, "\x00")),
+		})
+	}
+	return records, nil
+}
+
+// Close stops receiving records.
+func (l *Listener) Close() error {
+	return syscall.Close(l.fd)
+}
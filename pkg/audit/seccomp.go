@@ -0,0 +1,59 @@
+// Package audit provides helper functions to receive and parse the records of
+// the Linux kernel audit subsystem.
+package audit
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TypeSeccomp is the type of the records logged for the system calls matched
+// by a seccomp filter, AUDIT_SECCOMP in linux/audit.h.
+const TypeSeccomp = 1326
+
+// SeccompRecord is a record logged by the kernel for a system call matched by
+// a seccomp filter.
+type SeccompRecord struct {
+	Pid     int    // PID of the process, in the initial PID namespace
+	Arch    uint32 // Architecture of the system call, AUDIT_ARCH_* in linux/audit.h
+	Syscall int    // Number of the system call
+}
+
+// ParseSeccompRecord parses the text of a seccomp record, such as:
+//
+//	audit(1466542893.532:30): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=5321 comm="ls" exe="/bin/ls" sig=0 arch=c000003e syscall=59 compat=0 ip=0x7f4d0a3e0d27 code=0x7ffc0000
+func ParseSeccompRecord(data string) (*SeccompRecord, error) {
+	var (
+		r                           SeccompRecord
+		hasPid, hasArch, hasSyscall bool
+	)
+	for _, field := range strings.Fields(data) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+
+		var err error
+		switch kv[0] {
+		case "pid":
+			r.Pid, err = strconv.Atoi(kv[1])
+			hasPid = true
+		case "arch":
+			var arch uint64
+			arch, err = strconv.ParseUint(kv[1], 16, 32)
+			r.Arch = uint32(arch)
+			hasArch = true
+		case "syscall":
+			r.Syscall, err = strconv.Atoi(kv[1])
+			hasSyscall = true
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in seccomp record: %q", kv[0], kv[1])
+		}
+	}
+	if !hasPid || !hasArch || !hasSyscall {
+		return nil, fmt.Errorf("incomplete seccomp record: %q", data)
+	}
+	return &r, nil
+}
@@ -0,0 +1,43 @@
+package audit
+
+import "testing"
+
+func TestParseSeccompRecord(t *testing.T) {
+	data := `audit(1466542893.532:30): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=5321 comm="ls" exe="/bin/ls" sig=0 arch=c000003e syscall=59 compat=0 ip=0x7f4d0a3e0d27 code=0x7ffc0000`
+	r, err := ParseSeccompRecord(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := SeccompRecord{Pid: 5321, Arch: 0xc000003e, Syscall: 59}
+	if *r != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, *r)
+	}
+
+	// Untrusted strings are logged hex encoded when they contain spaces, so
+	// they can't be mistaken for fields.
+	data = `audit(1466542893.532:31): auid=4294967295 uid=0 gid=0 ses=4294967295 pid=42 comm=7069643D3120737973 exe="/bin/sh" sig=0 arch=40000003 syscall=11 compat=1 ip=0xf7701d09 code=0x7ffc0000`
+	r, err = ParseSeccompRecord(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = SeccompRecord{Pid: 42, Arch: 0x40000003, Syscall: 11}
+	if *r != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, *r)
+	}
+}
+
+func TestParseSeccompRecordInvalid(t *testing.T) {
+	invalids := []string{
+		``,
+		`audit(1466542893.532:30): pid=5321 comm="ls" syscall=59`,
+		`audit(1466542893.532:30): pid=5321 arch=c000003e`,
+		`audit(1466542893.532:30): pid=abc arch=c000003e syscall=59`,
+		`audit(1466542893.532:30): pid=5321 arch=x86_64 syscall=59`,
+		`audit(1466542893.532:30): pid=5321 arch=c000003e syscall=`,
+	}
+	for _, data := range invalids {
+		if _, err := ParseSeccompRecord(data); err == nil {
+			t.Fatalf("Expected an error parsing %q", data)
+		}
+	}
+}
@@ -17,10 +17,21 @@ var (
 
 // Download requests a given URL and returns an io.Reader.
 func Download(url string) (resp *http.Response, err error) {
-	if resp, err = http.Get(url); err != nil {
+	req, err := http.NewRequest("GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	return DownloadRequest(req)
+}
+
+// DownloadRequest sends the given request, which allows setting headers
+// such as credentials, and returns the response like Download.
+func DownloadRequest(req *http.Request) (resp *http.Response, err error) {
+	if resp, err = http.DefaultClient.Do(req); err != nil {
 		return nil, err
 	}
 	if resp.StatusCode >= 400 {
+		resp.Body.Close()
 		return nil, fmt.Errorf("Got HTTP status code >= 400: %s", resp.Status)
 	}
 	return resp, nil
@@ -41,6 +41,37 @@ func TestDownload400Errors(t *testing.T) {
 	}
 }
 
+func TestDownloadRequest(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, password, ok := r.BasicAuth()
+		if !ok || user != "user" || password != "secret" {
+			http.Error(w, "missing credentials", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, "authenticated")
+	}))
+	defer ts.Close()
+
+	req, err := http.NewRequest("GET", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DownloadRequest(req); err == nil {
+		t.Fatal("Expected an error without credentials")
+	}
+
+	req.SetBasicAuth("user", "secret")
+	response, err := DownloadRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil || string(actual) != "authenticated" {
+		t.Fatalf("Expected the response %q, got err:%v, actual:%s", "authenticated", err, string(actual))
+	}
+}
+
 func TestDownloadOtherErrors(t *testing.T) {
 	if _, err := Download("I'm not an url.."); err == nil || !strings.Contains(err.Error(), "unsupported protocol scheme") {
 		t.Fatalf("Expected an error with 'unsupported protocol scheme', got %v", err)
@@ -0,0 +1,162 @@
+// Package procconnector receives the process events of the Linux kernel proc
+// connector.
+package procconnector
+
+import (
+	"encoding/binary"
+	"syscall"
+
+	"github.com/vishvananda/netlink/nl"
+)
+
+const (
+	// cnIdxProc and cnValProc identify the proc connector, CN_IDX_PROC and
+	// CN_VAL_PROC in linux/connector.h.
+	cnIdxProc = 1
+	cnValProc = 1
+
+	// procCnMcastListen subscribes to the events, PROC_CN_MCAST_LISTEN in
+	// linux/cn_proc.h.
+	procCnMcastListen = 1
+
+	// sizeofCnMsg is the size of struct cn_msg, which precedes the event.
+	sizeofCnMsg = 20
+	// eventDataOffset is the offset of the event data, after the what, cpu
+	// and timestamp_ns fields of struct proc_event.
+	eventDataOffset = sizeofCnMsg + 16
+)
+
+// EventType is the type of a process event, the what field of struct
+// proc_event in linux/cn_proc.h.
+type EventType uint32
+
+const (
+	// Fork is sent when a thread creates a thread or a process.
+	Fork EventType = 0x00000001
+	// Exit is sent when a thread exits.
+	Exit EventType = 0x80000000
+)
+
+// Event is a process event sent by the kernel. The PIDs are those of the
+// initial PID namespace.
+type Event struct {
+	Type EventType
+	Pid  int // PID of the thread, the new one for a fork
+	Tgid int // PID of its process, the thread group
+	// ParentPid and ParentTgid are the thread which forked and its
+	// process, for a fork.
+	ParentPid  int
+	ParentTgid int
+}
+
+// Listener receives the fork and exit events of the kernel proc connector.
+// It requires the CAP_NET_ADMIN capability in the initial network namespace.
+type Listener struct {
+	fd  int
+	buf []byte
+}
+
+// NewListener returns a Listener subscribed to the process events of the
+// kernel.
+func NewListener() (*Listener, error) {
+	fd, err := syscall.Socket(syscall.AF_NETLINK, syscall.SOCK_DGRAM|syscall.SOCK_CLOEXEC, syscall.NETLINK_CONNECTOR)
+	if err != nil {
+		return nil, err
+	}
+	sa := &syscall.SockaddrNetlink{
+		Family: syscall.AF_NETLINK,
+		Groups: cnIdxProc,
+	}
+	if err := syscall.Bind(fd, sa); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+
+	// The subscription is a netlink message holding a struct cn_msg for
+	// the proc connector, followed by the operation.
+	msg := make([]byte, syscall.NLMSG_HDRLEN+sizeofCnMsg+4)
+	e := nl.NativeEndian()
+	e.PutUint32(msg[0:], uint32(len(msg)))
+	e.PutUint16(msg[4:], syscall.NLMSG_DONE)
+	cn := msg[syscall.NLMSG_HDRLEN:]
+	e.PutUint32(cn[0:], cnIdxProc)
+	e.PutUint32(cn[4:], cnValProc)
+	e.PutUint16(cn[16:], 4)
+	e.PutUint32(cn[sizeofCnMsg:], procCnMcastListen)
+	if err := syscall.Sendto(fd, msg, 0, &syscall.SockaddrNetlink{Family: syscall.AF_NETLINK}); err != nil {
+		syscall.Close(fd)
+		return nil, err
+	}
+	return &Listener{fd: fd, buf: make([]byte, 64*1024)}, nil
+}
+
+// Receive waits for events and returns the fork and exit events among them.
+// It returns syscall.ENOBUFS if events were lost because they weren't
+// received fast enough.
+func (l *Listener) Receive() ([]Event, error) {
+	var (
+		n   int
+		err error
+	)
+	for {
+		n, _, err = syscall.Recvfrom(l.fd, l.buf, 0)
+		if err != syscall.EINTR {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	msgs, err := syscall.ParseNetlinkMessage(l.buf[:n])
+	if err != nil {
+		return nil, err
+	}
+	var events []Event
+	for _, m := range msgs {
+		if ev, ok := parseEvent(m.Data, nl.NativeEndian()); ok {
+			events = append(events, ev)
+		}
+	}
+	return events, nil
+}
+
+// Close stops receiving events.
+func (l *Listener) Close() error {
+	return syscall.Close(l.fd)
+}
+
+// parseEvent parses the struct cn_msg and struct proc_event of a netlink
+// message, and returns the event if it's a fork or exit event of the proc
+// connector.
+func parseEvent(data []byte, e binary.ByteOrder) (Event, bool) {
+	if len(data) < eventDataOffset+8 {
+		return Event{}, false
+	}
+	if e.Uint32(data[0:]) != cnIdxProc || e.Uint32(data[4:]) != cnValProc {
+		return Event{}, false
+	}
+	d := data[eventDataOffset:]
+	switch t := EventType(e.Uint32(data[sizeofCnMsg:])); t {
+	case Exit:
+		return Event{
+			Type: t,
+			Pid:  int(e.Uint32(d[0:])),
+			Tgid: int(e.Uint32(d[4:])),
+		}, true
+	case Fork:
+		// The parent comes first: parent_pid, parent_tgid, child_pid
+		// and child_tgid.
+		if len(d) < 16 {
+			return Event{}, false
+		}
+		return Event{
+			Type:       t,
+			Pid:        int(e.Uint32(d[8:])),
+			Tgid:       int(e.Uint32(d[12:])),
+			ParentPid:  int(e.Uint32(d[0:])),
+			ParentTgid: int(e.Uint32(d[4:])),
+		}, true
+	}
+	return Event{}, false
+}
@@ -0,0 +1,49 @@
+package procconnector
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestParseEvent(t *testing.T) {
+	e := binary.LittleEndian
+	data := make([]byte, eventDataOffset+16)
+	e.PutUint32(data[0:], cnIdxProc)
+	e.PutUint32(data[4:], cnValProc)
+	e.PutUint32(data[sizeofCnMsg:], uint32(Exit))
+	e.PutUint32(data[eventDataOffset:], 5322)
+	e.PutUint32(data[eventDataOffset+4:], 5321)
+
+	ev, ok := parseEvent(data, e)
+	if !ok {
+		t.Fatal("Expected an exit event")
+	}
+	if expected := (Event{Type: Exit, Pid: 5322, Tgid: 5321}); ev != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, ev)
+	}
+
+	e.PutUint32(data[sizeofCnMsg:], uint32(Fork))
+	e.PutUint32(data[eventDataOffset+8:], 5330)
+	e.PutUint32(data[eventDataOffset+12:], 5330)
+	ev, ok = parseEvent(data, e)
+	if !ok {
+		t.Fatal("Expected a fork event")
+	}
+	if expected := (Event{Type: Fork, Pid: 5330, Tgid: 5330, ParentPid: 5322, ParentTgid: 5321}); ev != expected {
+		t.Fatalf("Expected %+v, got %+v", expected, ev)
+	}
+
+	// PROC_EVENT_EXEC
+	e.PutUint32(data[sizeofCnMsg:], 0x00000002)
+	if _, ok := parseEvent(data, e); ok {
+		t.Fatal("Expected an exec event to be ignored")
+	}
+
+	e.PutUint32(data[sizeofCnMsg:], uint32(Fork))
+	if _, ok := parseEvent(data[:eventDataOffset+8], e); ok {
+		t.Fatal("Expected a truncated fork event to be ignored")
+	}
+	if _, ok := parseEvent(data[:eventDataOffset], e); ok {
+		t.Fatal("Expected a truncated event to be ignored")
+	}
+}
@@ -0,0 +1,147 @@
+// Package ratelimit limits the bandwidth of readers. A Limiter can be shared
+// by several readers to limit their total bandwidth.
+package ratelimit
+
+import (
+	"io"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+// maxChunk is the maximum number of bytes read at once by a limited reader,
+// so that a reader with a low limit doesn't transfer in bursts.
+const maxChunk = 32 * 1024
+
+// Limiter is a token bucket limiting a bandwidth in bytes per second. It
+// allows bursts of up to one second of bandwidth. A Limiter without a limit
+// doesn't throttle. Limiter is goroutine-safe.
+type Limiter struct {
+	mu     sync.Mutex
+	limit  int64
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter creates a Limiter of bytesPerSec bytes per second. If
+// bytesPerSec is 0, the Limiter has no limit.
+func NewLimiter(bytesPerSec int64) *Limiter {
+	l := &Limiter{}
+	l.SetLimit(bytesPerSec)
+	return l
+}
+
+// Limit returns the limit of l in bytes per second, or 0 if it has none.
+func (l *Limiter) Limit() int64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// SetLimit sets the limit of l to bytesPerSec bytes per second. If
+// bytesPerSec is 0, the limit is removed.
+func (l *Limiter) SetLimit(bytesPerSec int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if bytesPerSec < 0 {
+		bytesPerSec = 0
+	}
+	l.limit = bytesPerSec
+	l.tokens = float64(bytesPerSec)
+	l.last = time.Now()
+}
+
+// reserve takes n bytes from the bucket, and returns how long the caller
+// must wait before transferring them.
+func (l *Limiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit == 0 {
+		return 0
+	}
+
+	now := time.Now()
+	rate := float64(l.limit)
+	l.tokens += now.Sub(l.last).Seconds() * rate
+	if l.tokens > rate {
+		l.tokens = rate
+	}
+	l.last = now
+
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / rate * float64(time.Second))
+}
+
+// WaitN blocks until n bytes may be transferred, or ctx is done.
+func (l *Limiter) WaitN(ctx context.Context, n int) error {
+	d := l.reserve(n)
+	if d == 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// MinLimit returns the lowest limit of limiters in bytes per second, or 0 if
+// none of them has a limit. Nil limiters are ignored.
+func MinLimit(limiters ...*Limiter) int64 {
+	var min int64
+	for _, l := range limiters {
+		if l == nil {
+			continue
+		}
+		if limit := l.Limit(); limit != 0 && (min == 0 || limit < min) {
+			min = limit
+		}
+	}
+	return min
+}
+
+type reader struct {
+	ctx      context.Context
+	in       io.ReadCloser
+	limiters []*Limiter
+}
+
+// NewReader returns a ReadCloser reading from in, within the limits of all
+// limiters. Reads return the error of ctx if it is done while waiting for
+// a limiter. Nil limiters are ignored.
+func NewReader(ctx context.Context, in io.ReadCloser, limiters ...*Limiter) io.ReadCloser {
+	r := &reader{ctx: ctx, in: in}
+	for _, l := range limiters {
+		if l != nil {
+			r.limiters = append(r.limiters, l)
+		}
+	}
+	if len(r.limiters) == 0 {
+		return in
+	}
+	return r
+}
+
+func (r *reader) Read(p []byte) (int, error) {
+	if len(p) > maxChunk {
+		p = p[:maxChunk]
+	}
+	n, err := r.in.Read(p)
+	for _, l := range r.limiters {
+		if waitErr := l.WaitN(r.ctx, n); waitErr != nil {
+			return n, waitErr
+		}
+	}
+	return n, err
+}
+
+func (r *reader) Close() error {
+	return r.in.Close()
+}
@@ -0,0 +1,60 @@
+package ratelimit
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestReaderLimit(t *testing.T) {
+	const limit = 64 * 1024
+	data := make([]byte, 3*limit)
+	l := NewLimiter(limit)
+
+	start := time.Now()
+	r := NewReader(context.Background(), ioutil.NopCloser(bytes.NewReader(data)), l)
+	read, err := ioutil.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(read) != len(data) {
+		t.Fatalf("Expected %d bytes, got %d", len(data), len(read))
+	}
+	// The first second of bandwidth is a burst, so 3 seconds of data take
+	// at least 2 seconds.
+	if elapsed := time.Since(start); elapsed < 2*time.Second-100*time.Millisecond {
+		t.Fatalf("Expected reading %d bytes at %d bytes/s to take 2s, took %s", len(data), limit, elapsed)
+	}
+}
+
+func TestReaderNoLimit(t *testing.T) {
+	in := ioutil.NopCloser(bytes.NewReader(nil))
+	if r := NewReader(context.Background(), in, nil); r != in {
+		t.Fatal("Expected a reader without limiters to be returned as is")
+	}
+	if d := NewLimiter(0).reserve(1 << 30); d != 0 {
+		t.Fatalf("Expected a limiter without a limit not to throttle, got %s", d)
+	}
+}
+
+func TestReaderCancel(t *testing.T) {
+	l := NewLimiter(1024)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := NewReader(ctx, ioutil.NopCloser(bytes.NewReader(make([]byte, 4096))), l)
+	if _, err := ioutil.ReadAll(r); err != context.Canceled {
+		t.Fatalf("Expected %v, got %v", context.Canceled, err)
+	}
+}
+
+func TestMinLimit(t *testing.T) {
+	if min := MinLimit(nil, NewLimiter(0)); min != 0 {
+		t.Fatalf("Expected no limit, got %d", min)
+	}
+	if min := MinLimit(NewLimiter(0), NewLimiter(2048), nil, NewLimiter(1024)); min != 1024 {
+		t.Fatalf("Expected a limit of 1024, got %d", min)
+	}
+}
@@ -5,6 +5,7 @@ package seccomp
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 
 	"github.com/docker/engine-api/types"
 	"github.com/opencontainers/specs/specs-go"
@@ -12,11 +13,63 @@ import (
 
 //go:generate go run -tags 'seccomp' generate.go
 
+// ActLog is the action of the audit profile, which logs the system calls and
+// allows them. It requires Linux 4.14 or later, and isn't part of the
+// vendored specs.
+const ActLog = types.Action("SCMP_ACT_LOG")
+
 // GetDefaultProfile returns the default seccomp profile.
 func GetDefaultProfile() (*specs.Seccomp, error) {
 	return setupSeccomp(DefaultProfile)
 }
 
+// GetAuditProfile returns the profile of the seccomp audit mode, which logs
+// the system calls rather than blocking them.
+func GetAuditProfile() (*specs.Seccomp, error) {
+	return setupSeccomp(&types.Seccomp{
+		DefaultAction: ActLog,
+		Architectures: defaultArchitectures(),
+	})
+}
+
+// baselineSyscalls are allowed by the profiles learned in audit mode even if
+// they weren't seen, since a process can't exit or return from a signal
+// handler without them.
+var baselineSyscalls = []string{"exit", "exit_group", "rt_sigreturn"}
+
+// ProfileFromSyscalls returns a profile which only allows the given system
+// calls, and those any process needs, for the architectures of the default
+// profile.
+func ProfileFromSyscalls(names []string) *types.Seccomp {
+	profile := &types.Seccomp{
+		DefaultAction: types.ActErrno,
+		Architectures: defaultArchitectures(),
+		Syscalls:      []*types.Syscall{},
+	}
+
+	sorted := append(append([]string(nil), names...), baselineSyscalls...)
+	sort.Strings(sorted)
+	for i, name := range sorted {
+		if i > 0 && name == sorted[i-1] {
+			continue
+		}
+		profile.Syscalls = append(profile.Syscalls, &types.Syscall{
+			Name:   name,
+			Action: types.ActAllow,
+			Args:   []*types.Arg{},
+		})
+	}
+	return profile
+}
+
+func defaultArchitectures() []types.Arch {
+	archs := []types.Arch{}
+	if DefaultProfile != nil {
+		archs = append(archs, DefaultProfile.Architectures...)
+	}
+	return archs
+}
+
 // LoadProfile takes a file path and decodes the seccomp profile.
 func LoadProfile(body string) (*specs.Seccomp, error) {
 	var config types.Seccomp
@@ -3,8 +3,13 @@
 package seccomp
 
 import (
+	"encoding/json"
 	"io/ioutil"
+	"reflect"
 	"testing"
+
+	"github.com/docker/engine-api/types"
+	"github.com/opencontainers/specs/specs-go"
 )
 
 func TestLoadProfile(t *testing.T) {
@@ -26,3 +31,39 @@ func TestLoadDefaultProfile(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestProfileFromSyscalls(t *testing.T) {
+	profile := ProfileFromSyscalls([]string{"write", "execve", "read", "write"})
+	if profile.DefaultAction != types.ActErrno {
+		t.Fatalf("Expected default action %s, got %s", types.ActErrno, profile.DefaultAction)
+	}
+	var names []string
+	for _, call := range profile.Syscalls {
+		if call.Action != types.ActAllow {
+			t.Fatalf("Expected %s to be allowed, got %s", call.Name, call.Action)
+		}
+		names = append(names, call.Name)
+	}
+	if expected := []string{"execve", "exit", "exit_group", "read", "rt_sigreturn", "write"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Expected syscalls %v, got %v", expected, names)
+	}
+
+	// The profile is in the format of the profiles given to the daemon.
+	b, err := json.Marshal(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadProfile(string(b)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetAuditProfile(t *testing.T) {
+	profile, err := GetAuditProfile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.DefaultAction != specs.Action(ActLog) || len(profile.Syscalls) != 0 {
+		t.Fatalf("Expected a profile logging every syscall, got %+v", profile)
+	}
+}
@@ -18,6 +18,11 @@ type ServiceOptions struct {
 	Mirrors            []string `json:"registry-mirrors,omitempty"`
 	InsecureRegistries []string `json:"insecure-registries,omitempty"`
 
+	// RegistryMirrors holds the mirrors of registries other than the
+	// official registry, by registry name. Pulls try the mirrors in order
+	// before falling back to the registry itself.
+	RegistryMirrors map[string][]string `json:"registry-mirrors-for,omitempty"`
+
 	// V2Only controls access to legacy registries.  If it is set to true via the
 	// command line flag the daemon will not attempt to contact v1 legacy registries
 	V2Only bool `json:"disable-legacy-registry,omitempty"`
@@ -27,6 +32,9 @@ type ServiceOptions struct {
 type serviceConfig struct {
 	registrytypes.ServiceConfig
 	V2Only bool
+	// RegistryMirrors holds the mirrors of registries other than the
+	// official registry, by registry name.
+	RegistryMirrors map[string][]string
 }
 
 var (
@@ -77,9 +85,78 @@ func (options *ServiceOptions) InstallCliFlags(cmd *flag.FlagSet, usageFn func(s
 	insecureRegistries := opts.NewNamedListOptsRef("insecure-registries", &options.InsecureRegistries, ValidateIndexName)
 	cmd.Var(insecureRegistries, []string{"-insecure-registry"}, usageFn("Enable insecure registry communication"))
 
+	registryMirrors := &registryMirrorsOpt{values: &options.RegistryMirrors}
+	cmd.Var(registryMirrors, []string{"-registry-mirror-for"}, usageFn("Preferred mirror of a registry, in the form registry=mirror"))
+
 	cmd.BoolVar(&options.V2Only, []string{"-disable-legacy-registry"}, false, usageFn("Do not contact legacy registries"))
 }
 
+// registryMirrorsOpt is a flag value adding a mirror, in the form
+// registry=mirror, to the mirrors of each registry.
+type registryMirrorsOpt struct {
+	values *map[string][]string
+}
+
+// Set validates and adds a mirror of a registry.
+func (o *registryMirrorsOpt) Set(val string) error {
+	parts := strings.SplitN(val, "=", 2)
+	if len(parts) != 2 {
+		return fmt.Errorf("invalid registry mirror %q, must be in the form registry=mirror", val)
+	}
+	if *o.values == nil {
+		*o.values = make(map[string][]string)
+	}
+	mirrors := (*o.values)[parts[0]]
+	(*o.values)[parts[0]] = append(mirrors, parts[1])
+	return ValidateRegistryMirrors(*o.values)
+}
+
+// String returns the mirrors of each registry.
+func (o *registryMirrorsOpt) String() string {
+	if *o.values == nil {
+		return ""
+	}
+	return fmt.Sprintf("%v", *o.values)
+}
+
+// Name returns the name of the option in the configuration file.
+func (o *registryMirrorsOpt) Name() string {
+	return "registry-mirrors-for"
+}
+
+// ValidateRegistryMirrors validates the mirrors of each registry. The
+// official registry is configured with --registry-mirror, and mirrors can't
+// have mirrors.
+func ValidateRegistryMirrors(registryMirrors map[string][]string) error {
+	for name, mirrors := range registryMirrors {
+		indexName, err := ValidateIndexName(name)
+		if err != nil {
+			return err
+		}
+		if indexName == "" || strings.Contains(indexName, "/") {
+			return fmt.Errorf("invalid registry %q for mirrors, must be in the form host[:port]", name)
+		}
+		if indexName == IndexName {
+			return fmt.Errorf("mirrors of the official registry are set with registry-mirrors")
+		}
+		for _, mirror := range mirrors {
+			if _, err := ValidateMirror(withMirrorScheme(mirror)); err != nil {
+				return fmt.Errorf("invalid mirror %q of registry %s: %v", mirror, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// withMirrorScheme returns mirror with the https scheme added if it has no
+// scheme.
+func withMirrorScheme(mirror string) string {
+	if !strings.HasPrefix(mirror, "http://") && !strings.HasPrefix(mirror, "https://") {
+		return "https://" + mirror
+	}
+	return mirror
+}
+
 // newServiceConfig returns a new instance of ServiceConfig
 func newServiceConfig(options ServiceOptions) *serviceConfig {
 	// Localhost is by default considered as an insecure registry
@@ -97,7 +174,17 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 			// and Mirrors are only for the official registry anyways.
 			Mirrors: options.Mirrors,
 		},
-		V2Only: options.V2Only,
+		V2Only:          options.V2Only,
+		RegistryMirrors: make(map[string][]string),
+	}
+	for name, mirrors := range options.RegistryMirrors {
+		indexName, err := ValidateIndexName(name)
+		if err != nil {
+			continue
+		}
+		for _, mirror := range mirrors {
+			config.RegistryMirrors[indexName] = append(config.RegistryMirrors[indexName], withMirrorScheme(mirror))
+		}
 	}
 	// Split --insecure-registry into CIDR and registry-specific settings.
 	for _, r := range options.InsecureRegistries {
@@ -110,7 +197,7 @@ func newServiceConfig(options ServiceOptions) *serviceConfig {
 			// Assume `host:port` if not CIDR.
 			config.IndexConfigs[r] = &registrytypes.IndexInfo{
 				Name:     r,
-				Mirrors:  make([]string, 0),
+				Mirrors:  append([]string{}, config.RegistryMirrors[r]...),
 				Secure:   false,
 				Official: false,
 			}
@@ -230,7 +317,7 @@ func newIndexInfo(config *serviceConfig, indexName string) (*registrytypes.Index
 	// Construct a non-configured index info.
 	index := &registrytypes.IndexInfo{
 		Name:     indexName,
-		Mirrors:  make([]string, 0),
+		Mirrors:  append([]string{}, config.RegistryMirrors[indexName]...),
 		Official: false,
 	}
 	index.Secure = isSecureIndex(config, indexName)
@@ -47,3 +47,51 @@ func TestValidateMirror(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateRegistryMirrors(t *testing.T) {
+	valid := []map[string][]string{
+		nil,
+		{"registry.corp:5000": {"mirror-1.com", "https://mirror-2.com:5000"}},
+		{"registry.corp": {"http://127.0.0.1:5000"}, "other.corp": {}},
+	}
+	invalid := []map[string][]string{
+		{"docker.io": {"mirror-1.com"}},
+		{"index.docker.io": {"mirror-1.com"}},
+		{"": {"mirror-1.com"}},
+		{"-registry.corp": {"mirror-1.com"}},
+		{"registry.corp/path": {"mirror-1.com"}},
+		{"registry.corp": {"ftp://mirror-1.com"}},
+		{"registry.corp": {"https://mirror-1.com/v2/"}},
+	}
+
+	for _, mirrors := range valid {
+		if err := ValidateRegistryMirrors(mirrors); err != nil {
+			t.Errorf("ValidateRegistryMirrors(%v) got %s", mirrors, err)
+		}
+	}
+	for _, mirrors := range invalid {
+		if err := ValidateRegistryMirrors(mirrors); err == nil {
+			t.Errorf("ValidateRegistryMirrors(%v) should have failed", mirrors)
+		}
+	}
+}
+
+func TestRegistryMirrorsOpt(t *testing.T) {
+	var mirrors map[string][]string
+	opt := &registryMirrorsOpt{values: &mirrors}
+	for _, value := range []string{"registry.corp:5000=mirror-1.com", "registry.corp:5000=https://mirror-2.com", "other.corp=mirror-3.com"} {
+		if err := opt.Set(value); err != nil {
+			t.Fatalf("Expected %q to be valid, got error: %v", value, err)
+		}
+	}
+	if len(mirrors) != 2 || len(mirrors["registry.corp:5000"]) != 2 || mirrors["registry.corp:5000"][1] != "https://mirror-2.com" {
+		t.Fatalf("Expected the mirrors to be added in order, got %v", mirrors)
+	}
+
+	for _, value := range []string{"registry.corp", "docker.io=mirror-1.com", "registry.corp=ftp://mirror-1.com"} {
+		var mirrors map[string][]string
+		if err := (&registryMirrorsOpt{values: &mirrors}).Set(value); err == nil {
+			t.Fatalf("Expected %q to be invalid", value)
+		}
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httputil"
 	"net/url"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -684,6 +685,64 @@ func TestMirrorEndpointLookup(t *testing.T) {
 	}
 }
 
+func TestRegistryMirrorEndpointLookup(t *testing.T) {
+	s := NewService(ServiceOptions{
+		RegistryMirrors: map[string][]string{
+			"registry.corp:5000": {"mirror-1.corp", "http://mirror-2.corp:5000"},
+		},
+	})
+
+	pullAPIEndpoints, err := s.LookupPullEndpoints("registry.corp:5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pullHosts []string
+	for _, endpoint := range pullAPIEndpoints {
+		if endpoint.Version == APIVersion2 {
+			pullHosts = append(pullHosts, endpoint.URL.String())
+		}
+	}
+	expected := []string{"https://mirror-1.corp", "http://mirror-2.corp:5000", "https://registry.corp:5000"}
+	if !reflect.DeepEqual(pullHosts, expected) {
+		t.Fatalf("Expected pull endpoints %v, got %v", expected, pullHosts)
+	}
+	if !pullAPIEndpoints[0].Mirror || !pullAPIEndpoints[0].TrimHostname {
+		t.Fatalf("Expected %s to be a mirror", pullAPIEndpoints[0].URL)
+	}
+
+	pushAPIEndpoints, err := s.LookupPushEndpoints("registry.corp:5000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, endpoint := range pushAPIEndpoints {
+		if endpoint.Mirror {
+			t.Fatalf("Push endpoints should not contain mirror %s", endpoint.URL)
+		}
+	}
+
+	otherAPIEndpoints, err := s.LookupPullEndpoints("other.corp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, endpoint := range otherAPIEndpoints {
+		if endpoint.Mirror {
+			t.Fatalf("Pull endpoints of another registry should not contain mirror %s", endpoint.URL)
+		}
+	}
+
+	name, err := reference.WithName("registry.corp:5000/test/image")
+	if err != nil {
+		t.Fatal(err)
+	}
+	repoInfo, err := s.ResolveRepository(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(repoInfo.Index.Mirrors, expected[:2]) {
+		t.Fatalf("Expected the index to have mirrors %v, got %v", expected[:2], repoInfo.Index.Mirrors)
+	}
+}
+
 func TestPushRegistryTag(t *testing.T) {
 	r := spawnTestRegistrySession(t)
 	repoRef, err := reference.ParseNamed(REPO)
@@ -16,7 +16,16 @@ const (
 // RestartManager defines object that controls container restarting rules.
 type RestartManager interface {
 	Cancel() error
-	ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool) (bool, chan error, error)
+	ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool, executionDuration time.Duration) (bool, chan error, error)
+}
+
+// State is the backoff state of a restart manager.
+type State struct {
+	// Delay is the delay before the last, or ongoing, restart.
+	Delay time.Duration
+	// RestartsInWindow is the number of restarts in the current restart
+	// window of the policy, if it has one.
+	RestartsInWindow int
 }
 
 type restartManager struct {
@@ -25,6 +34,7 @@ type restartManager struct {
 	policy       container.RestartPolicy
 	restartCount int
 	timeout      time.Duration
+	restarts     []time.Time // times of the restarts in the current window
 	active       bool
 	cancel       chan struct{}
 	canceled     bool
@@ -41,7 +51,32 @@ func (rm *restartManager) SetPolicy(policy container.RestartPolicy) {
 	rm.Unlock()
 }
 
-func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool) (bool, chan error, error) {
+// State returns the current backoff state of the restart manager.
+func (rm *restartManager) State() State {
+	rm.Lock()
+	defer rm.Unlock()
+	rm.pruneRestarts(time.Now())
+	return State{
+		Delay:            rm.timeout,
+		RestartsInWindow: len(rm.restarts),
+	}
+}
+
+// pruneRestarts forgets the restarts which happened before the restart
+// window of the policy.
+func (rm *restartManager) pruneRestarts(now time.Time) {
+	if rm.policy.Window <= 0 {
+		rm.restarts = nil
+		return
+	}
+	i := 0
+	for i < len(rm.restarts) && now.Sub(rm.restarts[i]) > rm.policy.Window {
+		i++
+	}
+	rm.restarts = rm.restarts[i:]
+}
+
+func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped bool, executionDuration time.Duration) (bool, chan error, error) {
 	rm.Lock()
 	unlockOnExit := true
 	defer func() {
@@ -58,11 +93,20 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 		return false, nil, fmt.Errorf("invalid call on active restartmanager")
 	}
 
+	// A container which ran long enough is considered to have started
+	// successfully, the backoff starts again from the default timeout.
+	if resetAfter := rm.policy.ResetAfter; resetAfter > 0 && executionDuration >= resetAfter {
+		rm.timeout = 0
+	}
+
 	if rm.timeout == 0 {
 		rm.timeout = defaultTimeout
 	} else {
 		rm.timeout *= backoffMultiplier
 	}
+	if max := rm.policy.MaxDelay; max > 0 && rm.timeout > max {
+		rm.timeout = max
+	}
 
 	var restart bool
 	switch {
@@ -77,12 +121,21 @@ func (rm *restartManager) ShouldRestart(exitCode uint32, hasBeenManuallyStopped
 		}
 	}
 
+	now := time.Now()
+	rm.pruneRestarts(now)
+	if max := rm.policy.MaxRestartsInWindow; restart && max > 0 && len(rm.restarts) >= max {
+		restart = false
+	}
+
 	if !restart {
 		rm.active = false
 		return false, nil, nil
 	}
 
 	rm.restartCount++
+	if rm.policy.Window > 0 {
+		rm.restarts = append(rm.restarts, now)
+	}
 
 	unlockOnExit = false
 	rm.active = true
@@ -1,3 +1,105 @@
 package restartmanager
 
-// FIXME
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/engine-api/types/container"
+)
+
+// restart asks rm whether to restart an exited container, and waits for the
+// restart delay if it should.
+func restart(t *testing.T, rm RestartManager, executionDuration time.Duration) bool {
+	restart, wait, err := rm.ShouldRestart(1, false, executionDuration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restart {
+		if err := <-wait; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return restart
+}
+
+func TestRestartManagerMaxDelay(t *testing.T) {
+	policy := container.RestartPolicy{Name: "always", MaxDelay: 300 * time.Millisecond}
+	rm := New(policy, 0).(*restartManager)
+
+	expected := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 300 * time.Millisecond, 300 * time.Millisecond}
+	for _, delay := range expected {
+		if !restart(t, rm, 0) {
+			t.Fatal("Expected the container to be restarted")
+		}
+		if state := rm.State(); state.Delay != delay {
+			t.Fatalf("Expected a delay of %v, got %v", delay, state.Delay)
+		}
+	}
+}
+
+func TestRestartManagerResetAfter(t *testing.T) {
+	policy := container.RestartPolicy{Name: "always", ResetAfter: time.Minute}
+	rm := New(policy, 0).(*restartManager)
+
+	restart(t, rm, 0)
+	restart(t, rm, 0)
+	if state := rm.State(); state.Delay != 200*time.Millisecond {
+		t.Fatalf("Expected a delay of 200ms, got %v", state.Delay)
+	}
+
+	restart(t, rm, 10*time.Second)
+	if state := rm.State(); state.Delay != 400*time.Millisecond {
+		t.Fatalf("Expected a delay of 400ms, got %v", state.Delay)
+	}
+
+	restart(t, rm, time.Minute)
+	if state := rm.State(); state.Delay != defaultTimeout {
+		t.Fatalf("Expected the delay to be reset to %v, got %v", defaultTimeout, state.Delay)
+	}
+}
+
+func TestRestartManagerNoResetAfter(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "always"}, 0).(*restartManager)
+
+	restart(t, rm, 0)
+	restart(t, rm, 0)
+	restart(t, rm, time.Hour)
+	if state := rm.State(); state.Delay != 400*time.Millisecond {
+		t.Fatalf("Expected a delay of 400ms, got %v", state.Delay)
+	}
+}
+
+func TestRestartManagerWindow(t *testing.T) {
+	policy := container.RestartPolicy{Name: "always", MaxDelay: time.Millisecond, Window: time.Minute, MaxRestartsInWindow: 2}
+	rm := New(policy, 0).(*restartManager)
+
+	for i := 0; i < 2; i++ {
+		if !restart(t, rm, 0) {
+			t.Fatalf("Expected restart %d to be allowed", i+1)
+		}
+	}
+	if state := rm.State(); state.RestartsInWindow != 2 {
+		t.Fatalf("Expected 2 restarts in window, got %d", state.RestartsInWindow)
+	}
+	if restart(t, rm, 0) {
+		t.Fatal("Expected the restart past the maximum in window to be refused")
+	}
+
+	// restarts out of the window don't count anymore
+	rm.restarts[0] = rm.restarts[0].Add(-2 * time.Minute)
+	if !restart(t, rm, 0) {
+		t.Fatal("Expected the restart to be allowed once a restart left the window")
+	}
+}
+
+func TestRestartManagerSetPolicy(t *testing.T) {
+	rm := New(container.RestartPolicy{Name: "always"}, 0).(*restartManager)
+	rm.SetPolicy(container.RestartPolicy{Name: "always", Window: time.Minute, MaxRestartsInWindow: 1})
+
+	if !restart(t, rm, 0) {
+		t.Fatal("Expected the container to be restarted")
+	}
+	if restart(t, rm, 0) {
+		t.Fatal("Expected the updated policy to refuse the restart")
+	}
+}
@@ -0,0 +1,142 @@
+package opts
+
+import (
+	"os"
+	"testing"
+
+	mounttypes "github.com/docker/engine-api/types/mount"
+)
+
+func TestMountOptString(t *testing.T) {
+	mount := MountOpt{
+		values: []mounttypes.Mount{
+			{
+				Type:   mounttypes.TypeBind,
+				Source: "/home/path",
+				Target: "/target",
+			},
+			{
+				Type:   mounttypes.TypeVolume,
+				Source: "foo",
+				Target: "/target/foo",
+			},
+		},
+	}
+	expected := "bind /home/path /target, volume foo /target/foo"
+	if out := mount.String(); out != expected {
+		t.Fatalf("Expected %q, got %q", expected, out)
+	}
+}
+
+func TestMountOptSetNoError(t *testing.T) {
+	for _, testcase := range []string{
+		// tests several aliases that should have same result.
+		"type=bind,target=/target,source=/source",
+		"type=bind,src=/source,dst=/target",
+		"type=bind,source=/source,dst=/target",
+		"type=bind,src=/source,target=/target",
+	} {
+		var mount MountOpt
+		if err := mount.Set(testcase); err != nil {
+			t.Fatalf("%s: %v", testcase, err)
+		}
+		mounts := mount.Value()
+		if len(mounts) != 1 {
+			t.Fatalf("%s: expected 1 mount, got %d", testcase, len(mounts))
+		}
+		expected := mounttypes.Mount{
+			Type:   mounttypes.TypeBind,
+			Source: "/source",
+			Target: "/target",
+		}
+		if mounts[0] != expected {
+			t.Fatalf("%s: expected %+v, got %+v", testcase, expected, mounts[0])
+		}
+	}
+}
+
+// TestMountOptDefaultType ensures that a mount without the type defaults to a
+// volume mount.
+func TestMountOptDefaultType(t *testing.T) {
+	var mount MountOpt
+	if err := mount.Set("target=/target,source=/foo"); err != nil {
+		t.Fatal(err)
+	}
+	if mount.values[0].Type != mounttypes.TypeVolume {
+		t.Fatalf("Expected type volume, got %q", mount.values[0].Type)
+	}
+}
+
+func TestMountOptSetErrors(t *testing.T) {
+	for value, expected := range map[string]string{
+		"":                                                "target is required",
+		"type=volume":                                     "target is required",
+		"type=volume,target":                              "invalid field 'target' must be a key=value pair",
+		"type=volume,target=/foo,invalid=foo":             "unexpected key 'invalid' in 'invalid=foo'",
+		"type=bind,target=/foo,readonly=no":               "invalid value for readonly: no",
+		"type=tmpfs,target=/foo,tmpfs-size=x":             "invalid value for tmpfs-size: x",
+		"type=tmpfs,target=/foo,tmpfs-mode=9":             "invalid value for tmpfs-mode: 9",
+		"type=bind,target=/foo,volume-nocopy":             "cannot mix 'volume-*' options with mount type 'bind'",
+		"type=volume,target=/foo,bind-propagation=shared": "cannot mix 'bind-*' options with mount type 'volume'",
+		"type=volume,target=/foo,tmpfs-size=1m":           "cannot mix 'tmpfs-*' options with mount type 'volume'",
+	} {
+		var mount MountOpt
+		err := mount.Set(value)
+		if err == nil || err.Error() != expected {
+			t.Fatalf("%q: expected error %q, got %v", value, expected, err)
+		}
+	}
+}
+
+func TestMountOptReadOnly(t *testing.T) {
+	for value, readOnly := range map[string]bool{
+		"type=bind,target=/foo,source=/bar":                false,
+		"type=bind,target=/foo,source=/bar,readonly":       true,
+		"type=bind,target=/foo,source=/bar,ro":             true,
+		"type=bind,target=/foo,source=/bar,readonly=true":  true,
+		"type=bind,target=/foo,source=/bar,readonly=false": false,
+		"type=bind,target=/foo,source=/bar,ro=0":           false,
+	} {
+		var mount MountOpt
+		if err := mount.Set(value); err != nil {
+			t.Fatalf("%s: %v", value, err)
+		}
+		if mount.values[0].ReadOnly != readOnly {
+			t.Fatalf("%s: expected readonly %v, got %v", value, readOnly, mount.values[0].ReadOnly)
+		}
+	}
+}
+
+func TestMountOptVolumeOptions(t *testing.T) {
+	var mount MountOpt
+	if err := mount.Set(`type=volume,source=foo,target=/foo,volume-nocopy,volume-driver=bar,volume-label=a=b,"volume-opt=o=size=10m,uid=1000"`); err != nil {
+		t.Fatal(err)
+	}
+	opts := mount.values[0].VolumeOptions
+	if opts == nil || !opts.NoCopy {
+		t.Fatalf("Expected nocopy to be set, got %+v", opts)
+	}
+	if opts.Labels["a"] != "b" {
+		t.Fatalf("Expected label a=b, got %v", opts.Labels)
+	}
+	if opts.DriverConfig.Name != "bar" {
+		t.Fatalf("Expected driver bar, got %q", opts.DriverConfig.Name)
+	}
+	if opts.DriverConfig.Options["o"] != "size=10m,uid=1000" {
+		t.Fatalf("Expected driver option o=size=10m,uid=1000, got %v", opts.DriverConfig.Options)
+	}
+}
+
+func TestMountOptTmpfsOptions(t *testing.T) {
+	var mount MountOpt
+	if err := mount.Set("type=tmpfs,target=/foo,tmpfs-size=128m,tmpfs-mode=1770"); err != nil {
+		t.Fatal(err)
+	}
+	opts := mount.values[0].TmpfsOptions
+	if opts == nil || opts.SizeBytes != 128*1024*1024 {
+		t.Fatalf("Expected a size of 128m, got %+v", opts)
+	}
+	if opts.Mode != os.FileMode(01770) {
+		t.Fatalf("Expected a mode of 1770, got %o", opts.Mode)
+	}
+}
@@ -30,6 +30,7 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 		flAttach            = opts.NewListOpts(ValidateAttach)
 		flVolumes           = opts.NewListOpts(nil)
 		flTmpfs             = opts.NewListOpts(nil)
+		flMounts            = &MountOpt{}
 		flBlkioWeightDevice = NewWeightdeviceOpt(ValidateWeightDevice)
 		flDeviceReadBps     = NewThrottledeviceOpt(ValidateThrottleBpsDevice)
 		flDeviceWriteBps    = NewThrottledeviceOpt(ValidateThrottleBpsDevice)
@@ -92,13 +93,20 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 		flIpcMode           = cmd.String([]string{"-ipc"}, "", "IPC namespace to use")
 		flPidsLimit         = cmd.Int64([]string{"-pids-limit"}, 0, "Tune container pids limit (set -1 for unlimited)")
 		flRestartPolicy     = cmd.String([]string{"-restart"}, "no", "Restart policy to apply when a container exits")
+		flRestartMaxDelay   = cmd.Duration([]string{"-restart-max-delay"}, 0, "Maximum delay between restarts of the container")
+		flRestartResetAfter = cmd.Duration([]string{"-restart-reset-after"}, 0, "Reset the delay between restarts once the container ran this long")
+		flRestartWindow     = cmd.Duration([]string{"-restart-window"}, 0, "Window in which the restarts of the container are limited by --restart-window-max")
+		flRestartWindowMax  = cmd.Int([]string{"-restart-window-max"}, 0, "Maximum number of restarts of the container within --restart-window")
 		flReadonlyRootfs    = cmd.Bool([]string{"-read-only"}, false, "Mount the container's root filesystem as read only")
 		flLoggingDriver     = cmd.String([]string{"-log-driver"}, "", "Logging driver for container")
 		flCgroupParent      = cmd.String([]string{"-cgroup-parent"}, "", "Optional parent cgroup for the container")
 		flVolumeDriver      = cmd.String([]string{"-volume-driver"}, "", "Optional volume driver for the container")
 		flStopSignal        = cmd.String([]string{"-stop-signal"}, signal.DefaultStopSignal, fmt.Sprintf("Signal to stop a container, %v by default", signal.DefaultStopSignal))
+		flStopTimeout       = cmd.Int([]string{"-stop-timeout"}, 0, "Timeout (in seconds) to stop a container, 10 by default")
 		flIsolation         = cmd.String([]string{"-isolation"}, "", "Container isolation technology")
 		flShmSize           = cmd.String([]string{"-shm-size"}, "", "Size of /dev/shm, default value is 64MB")
+		flRuntime           = cmd.String([]string{"-runtime"}, "", "Runtime to use for this container")
+		flInit              = cmd.Bool([]string{"-init"}, false, "Run an init inside the container that forwards signals and reaps processes")
 	)
 
 	cmd.Var(&flAttach, []string{"a", "-attach"}, "Attach to STDIN, STDOUT or STDERR")
@@ -109,6 +117,7 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 	cmd.Var(&flDeviceWriteIOps, []string{"-device-write-iops"}, "Limit write rate (IO per second) to a device")
 	cmd.Var(&flVolumes, []string{"v", "-volume"}, "Bind mount a volume")
 	cmd.Var(&flTmpfs, []string{"-tmpfs"}, "Mount a tmpfs directory")
+	cmd.Var(flMounts, []string{"-mount"}, "Attach a filesystem mount to the container")
 	cmd.Var(&flLinks, []string{"-link"}, "Add link to another container")
 	cmd.Var(&flAliases, []string{"-net-alias"}, "Add network-scoped alias for the container")
 	cmd.Var(&flDevices, []string{"-device"}, "Add a host device to the container")
@@ -331,6 +340,10 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 	if err != nil {
 		return nil, nil, nil, cmd, err
 	}
+	restartPolicy.MaxDelay = *flRestartMaxDelay
+	restartPolicy.ResetAfter = *flRestartResetAfter
+	restartPolicy.Window = *flRestartWindow
+	restartPolicy.MaxRestartsInWindow = *flRestartWindowMax
 
 	loggingOpts, err := parseLoggingOpts(*flLoggingDriver, flLoggingOpts.GetAll())
 	if err != nil {
@@ -397,6 +410,9 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 	if cmd.IsSet("-stop-signal") {
 		config.StopSignal = *flStopSignal
 	}
+	if cmd.IsSet("-stop-timeout") {
+		config.StopTimeout = flStopTimeout
+	}
 
 	hostConfig := &container.HostConfig{
 		Binds:           binds,
@@ -416,6 +432,7 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 		DNSOptions:     flDNSOptions.GetAllOrEmpty(),
 		ExtraHosts:     flExtraHosts.GetAll(),
 		VolumesFrom:    flVolumesFrom.GetAll(),
+		Mounts:         flMounts.Value(),
 		NetworkMode:    container.NetworkMode(*flNetMode),
 		IpcMode:        ipcMode,
 		PidMode:        pidMode,
@@ -435,6 +452,11 @@ func Parse(cmd *flag.FlagSet, args []string) (*container.Config, *container.Host
 		Resources:      resources,
 		Tmpfs:          tmpfs,
 		Sysctls:        flSysctls.GetAll(),
+		Runtime:        *flRuntime,
+	}
+
+	if cmd.IsSet("-init") {
+		hostConfig.Init = flInit
 	}
 
 	// When allocating stdin in attached mode, close stdin at client disconnect
@@ -529,7 +551,7 @@ func parseSecurityOpts(securityOpts []string) ([]string, error) {
 				return securityOpts, fmt.Errorf("Invalid --security-opt: %q", opt)
 			}
 		}
-		if con[0] == "seccomp" && con[1] != "unconfined" {
+		if con[0] == "seccomp" && con[1] != "unconfined" && con[1] != "audit" {
 			f, err := ioutil.ReadFile(con[1])
 			if err != nil {
 				return securityOpts, fmt.Errorf("opening seccomp profile (%s) failed: %v", con[1], err)
@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	flag "github.com/docker/docker/pkg/mflag"
 	"github.com/docker/docker/runconfig"
@@ -560,6 +561,32 @@ func TestParseModes(t *testing.T) {
 	if hostconfig.ShmSize != 134217728 {
 		t.Fatalf("Expected a valid ShmSize, got %d", hostconfig.ShmSize)
 	}
+	// runtime ok
+	_, hostconfig, _, _, err = parseRun([]string{"--runtime=secure", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostconfig.Runtime != "secure" {
+		t.Fatalf("Expected runtime secure, got %q", hostconfig.Runtime)
+	}
+	// init unset, defers to the daemon
+	_, hostconfig, _, _, err = parseRun([]string{"img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hostconfig.Init != nil {
+		t.Fatalf("Expected init to be unset, got %v", *hostconfig.Init)
+	}
+	// init explicitly set
+	for _, value := range []bool{true, false} {
+		_, hostconfig, _, _, err = parseRun([]string{fmt.Sprintf("--init=%v", value), "img", "cmd"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hostconfig.Init == nil || *hostconfig.Init != value {
+			t.Fatalf("Expected init to be %v, got %v", value, hostconfig.Init)
+		}
+	}
 }
 
 func TestParseRestartPolicy(t *testing.T) {
@@ -597,6 +624,45 @@ func TestParseRestartPolicy(t *testing.T) {
 	}
 }
 
+func TestParseRestartPolicyBackoff(t *testing.T) {
+	_, hostconfig, _, _, err := parseRun([]string{"--restart=always", "--restart-max-delay=1m", "--restart-reset-after=10s", "--restart-window=1h", "--restart-window-max=5", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := container.RestartPolicy{
+		Name:                "always",
+		MaxDelay:            time.Minute,
+		ResetAfter:          10 * time.Second,
+		Window:              time.Hour,
+		MaxRestartsInWindow: 5,
+	}
+	if hostconfig.RestartPolicy != expected {
+		t.Fatalf("Expected %v, got %v", expected, hostconfig.RestartPolicy)
+	}
+
+	if _, _, _, _, err := parseRun([]string{"--restart-max-delay=invalid", "img", "cmd"}); err == nil {
+		t.Fatal("Expected an error parsing an invalid duration, got none")
+	}
+}
+
+func TestParseStopTimeout(t *testing.T) {
+	config, _, _, _, err := parseRun([]string{"img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.StopTimeout != nil {
+		t.Fatalf("Expected no stop timeout, got %d", *config.StopTimeout)
+	}
+
+	config, _, _, _, err = parseRun([]string{"--stop-timeout=60", "img", "cmd"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.StopTimeout == nil || *config.StopTimeout != 60 {
+		t.Fatalf("Expected a stop timeout of 60, got %v", config.StopTimeout)
+	}
+}
+
 func TestParseLoggingOpts(t *testing.T) {
 	// logging opts ko
 	if _, _, _, _, err := parseRun([]string{"--log-driver=none", "--log-opt=anything", "img", "cmd"}); err == nil || err.Error() != "invalid logging opts for driver none" {
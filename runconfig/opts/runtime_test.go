@@ -0,0 +1,52 @@
+package opts
+
+import (
+	"testing"
+
+	"github.com/docker/engine-api/types"
+)
+
+func TestRuntimeOpt(t *testing.T) {
+	runtimes := map[string]types.Runtime{}
+	opt := NewNamedRuntimeOpt("runtimes", &runtimes, "runc")
+
+	if opt.Name() != "runtimes" {
+		t.Fatalf("Expected name runtimes, got %s", opt.Name())
+	}
+
+	if err := opt.Set("secure=/usr/local/bin/secure-runc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := opt.Set(" Other = other-runtime "); err != nil {
+		t.Fatal(err)
+	}
+
+	if rt, ok := runtimes["secure"]; !ok || rt.Path != "/usr/local/bin/secure-runc" {
+		t.Fatalf("Expected runtime secure=/usr/local/bin/secure-runc, got %v", runtimes)
+	}
+	if rt, ok := runtimes["other"]; !ok || rt.Path != "other-runtime" {
+		t.Fatalf("Expected runtime other=other-runtime, got %v", runtimes)
+	}
+
+	expected := "[other secure]"
+	if opt.String() != expected {
+		t.Fatalf("Expected %v, got %v", expected, opt.String())
+	}
+
+	invalids := map[string]string{
+		"secure":           "invalid runtime argument: secure",
+		"=path":            "invalid runtime argument: =path",
+		"name=":            "invalid runtime argument: name=",
+		"runc=/bin/runc":   "runtime name 'runc' is reserved",
+		"secure=/bin/true": "runtime 'secure' was already defined",
+	}
+	for val, expectedErr := range invalids {
+		if err := opt.Set(val); err == nil || err.Error() != expectedErr {
+			t.Fatalf("Expected error %q for %q, got %v", expectedErr, val, err)
+		}
+	}
+
+	if len(opt.GetMap()) != 2 {
+		t.Fatalf("Expected 2 runtimes, got %v", opt.GetMap())
+	}
+}
@@ -0,0 +1,184 @@
+package volume
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	mounttypes "github.com/docker/engine-api/types/mount"
+)
+
+var errBindNotExist = errors.New("bind source path does not exist")
+
+type errMountConfig struct {
+	mount *mounttypes.Mount
+	err   error
+}
+
+func (e *errMountConfig) Error() string {
+	return fmt.Sprintf("invalid mount config for type %q: %v", e.mount.Type, e.err.Error())
+}
+
+func errExtraField(name string) error {
+	return fmt.Errorf("field %s must not be specified", name)
+}
+
+func errMissingField(name string) error {
+	return fmt.Errorf("field %s must not be empty", name)
+}
+
+// ValidateMountConfig validates the configuration of a mount given with the
+// structured mount API: the fields that must be set, or not, for its type,
+// and the options of each type.
+func ValidateMountConfig(mnt *mounttypes.Mount) error {
+	if len(mnt.Target) == 0 {
+		return &errMountConfig{mnt, errMissingField("Target")}
+	}
+	target := filepath.Clean(filepath.FromSlash(mnt.Target))
+	if !filepath.IsAbs(target) {
+		return &errMountConfig{mnt, fmt.Errorf("invalid mount path: '%s' mount path must be absolute", mnt.Target)}
+	}
+	if target == string(os.PathSeparator) {
+		return &errMountConfig{mnt, fmt.Errorf("invalid mount path: '%s' can't be the root directory", mnt.Target)}
+	}
+
+	switch mnt.Type {
+	case mounttypes.TypeBind:
+		if len(mnt.Source) == 0 {
+			return &errMountConfig{mnt, errMissingField("Source")}
+		}
+		if mnt.VolumeOptions != nil {
+			return &errMountConfig{mnt, errExtraField("VolumeOptions")}
+		}
+		if mnt.TmpfsOptions != nil {
+			return &errMountConfig{mnt, errExtraField("TmpfsOptions")}
+		}
+		if mnt.BindOptions != nil && len(mnt.BindOptions.Propagation) > 0 {
+			if !propagationModes[string(mnt.BindOptions.Propagation)] {
+				return &errMountConfig{mnt, fmt.Errorf("invalid propagation mode: %s", mnt.BindOptions.Propagation)}
+			}
+		}
+		source := filepath.FromSlash(mnt.Source)
+		if !filepath.IsAbs(source) {
+			return &errMountConfig{mnt, fmt.Errorf("invalid mount source: '%s' bind source must be an absolute path", mnt.Source)}
+		}
+		if _, err := os.Stat(source); err != nil {
+			if !os.IsNotExist(err) {
+				return &errMountConfig{mnt, err}
+			}
+			return &errMountConfig{mnt, fmt.Errorf("%v: %s", errBindNotExist, mnt.Source)}
+		}
+	case mounttypes.TypeVolume:
+		if mnt.BindOptions != nil {
+			return &errMountConfig{mnt, errExtraField("BindOptions")}
+		}
+		if mnt.TmpfsOptions != nil {
+			return &errMountConfig{mnt, errExtraField("TmpfsOptions")}
+		}
+		if len(mnt.Source) > 0 {
+			if _, err := IsVolumeNameValid(mnt.Source); err != nil {
+				return &errMountConfig{mnt, err}
+			}
+			if filepath.IsAbs(filepath.FromSlash(mnt.Source)) {
+				return &errMountConfig{mnt, fmt.Errorf("invalid volume name: '%s' must not be a path, use a bind mount instead", mnt.Source)}
+			}
+		}
+	case mounttypes.TypeTmpfs:
+		if runtime.GOOS == "windows" {
+			return &errMountConfig{mnt, errors.New("tmpfs mounts are not supported on Windows")}
+		}
+		if len(mnt.Source) != 0 {
+			return &errMountConfig{mnt, errExtraField("Source")}
+		}
+		if mnt.BindOptions != nil {
+			return &errMountConfig{mnt, errExtraField("BindOptions")}
+		}
+		if mnt.VolumeOptions != nil {
+			return &errMountConfig{mnt, errExtraField("VolumeOptions")}
+		}
+		if _, err := ConvertTmpfsOptions(mnt.TmpfsOptions, mnt.ReadOnly); err != nil {
+			return &errMountConfig{mnt, err}
+		}
+	default:
+		return &errMountConfig{mnt, errors.New("mount type unknown")}
+	}
+	return nil
+}
+
+// ParseMountConfig validates a mount given with the structured mount API and
+// converts it to a mount point. volumeDriver is the driver used for volumes
+// which don't specify one.
+func ParseMountConfig(cfg mounttypes.Mount, volumeDriver string) (*MountPoint, error) {
+	if err := ValidateMountConfig(&cfg); err != nil {
+		return nil, err
+	}
+	mp := &MountPoint{
+		RW:          !cfg.ReadOnly,
+		Destination: filepath.Clean(filepath.FromSlash(cfg.Target)),
+		Type:        cfg.Type,
+		Spec:        cfg,
+	}
+
+	switch cfg.Type {
+	case mounttypes.TypeVolume:
+		mp.Name = cfg.Source
+		mp.Named = len(cfg.Source) > 0
+		mp.Driver = volumeDriver
+		mp.CopyData = DefaultCopyMode
+		if cfg.VolumeOptions != nil {
+			if cfg.VolumeOptions.DriverConfig != nil && len(cfg.VolumeOptions.DriverConfig.Name) > 0 {
+				mp.Driver = cfg.VolumeOptions.DriverConfig.Name
+			}
+			if cfg.VolumeOptions.NoCopy {
+				mp.CopyData = false
+			}
+		}
+	case mounttypes.TypeBind:
+		mp.Source = filepath.Clean(filepath.FromSlash(cfg.Source))
+		mp.Propagation = DefaultPropagationMode
+		if cfg.BindOptions != nil && len(cfg.BindOptions.Propagation) > 0 {
+			mp.Propagation = string(cfg.BindOptions.Propagation)
+		}
+	}
+	return mp, nil
+}
+
+// ConvertTmpfsOptions converts the options of a tmpfs mount to the option
+// string passed to mount(2), as used by --tmpfs.
+func ConvertTmpfsOptions(opt *mounttypes.TmpfsOptions, readOnly bool) (string, error) {
+	var rawOpts []string
+	if readOnly {
+		rawOpts = append(rawOpts, "ro")
+	}
+	if opt == nil {
+		return strings.Join(rawOpts, ","), nil
+	}
+
+	if opt.Mode != 0 {
+		if opt.Mode&^07777 != 0 {
+			return "", fmt.Errorf("invalid tmpfs mode: %o", opt.Mode)
+		}
+		rawOpts = append(rawOpts, fmt.Sprintf("mode=%o", opt.Mode))
+	}
+
+	if opt.SizeBytes < 0 {
+		return "", fmt.Errorf("invalid tmpfs size: %d, size must not be negative", opt.SizeBytes)
+	}
+	if opt.SizeBytes != 0 {
+		// Use the largest suffix the size is a multiple of.
+		size := opt.SizeBytes
+		suffix := ""
+		for _, s := range []string{"k", "m", "g"} {
+			if size%1024 != 0 {
+				break
+			}
+			size /= 1024
+			suffix = s
+		}
+		rawOpts = append(rawOpts, fmt.Sprintf("size=%d%s", size, suffix))
+	}
+	return strings.Join(rawOpts, ","), nil
+}
@@ -0,0 +1,122 @@
+//go:build !windows
+// +build !windows
+
+package volume
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	mounttypes "github.com/docker/engine-api/types/mount"
+)
+
+func TestValidateMountConfig(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "test-validate-mount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	valid := []mounttypes.Mount{
+		{Type: mounttypes.TypeBind, Source: tmpdir, Target: "/foo"},
+		{Type: mounttypes.TypeBind, Source: tmpdir, Target: "/foo", ReadOnly: true, BindOptions: &mounttypes.BindOptions{Propagation: mounttypes.PropagationRSlave}},
+		{Type: mounttypes.TypeVolume, Target: "/foo"},
+		{Type: mounttypes.TypeVolume, Source: "myvolume", Target: "/foo", VolumeOptions: &mounttypes.VolumeOptions{NoCopy: true}},
+		{Type: mounttypes.TypeTmpfs, Target: "/foo", TmpfsOptions: &mounttypes.TmpfsOptions{SizeBytes: 1 << 20, Mode: 01777}},
+	}
+	for _, mnt := range valid {
+		if err := ValidateMountConfig(&mnt); err != nil {
+			t.Fatalf("Expected %+v to be valid, got %v", mnt, err)
+		}
+	}
+
+	invalid := map[string]mounttypes.Mount{
+		"field Target must not be empty":                    {Type: mounttypes.TypeVolume, Source: "foo"},
+		"mount path must be absolute":                       {Type: mounttypes.TypeVolume, Target: "foo"},
+		"can't be the root directory":                       {Type: mounttypes.TypeVolume, Target: "/"},
+		"mount type unknown":                                {Type: "invalid", Target: "/foo"},
+		"field Source must not be empty":                    {Type: mounttypes.TypeBind, Target: "/foo"},
+		"bind source must be an absolute path":              {Type: mounttypes.TypeBind, Source: "foo", Target: "/foo"},
+		"bind source path does not exist":                   {Type: mounttypes.TypeBind, Source: tmpdir + "/missing", Target: "/foo"},
+		"invalid propagation mode: invalid":                 {Type: mounttypes.TypeBind, Source: tmpdir, Target: "/foo", BindOptions: &mounttypes.BindOptions{Propagation: "invalid"}},
+		`type "bind": field VolumeOptions must not be`:      {Type: mounttypes.TypeBind, Source: tmpdir, Target: "/foo", VolumeOptions: &mounttypes.VolumeOptions{}},
+		`type "volume": field BindOptions must not be`:      {Type: mounttypes.TypeVolume, Target: "/foo", BindOptions: &mounttypes.BindOptions{}},
+		"must not be a path, use a bind mount instead":      {Type: mounttypes.TypeVolume, Source: tmpdir, Target: "/foo"},
+		`type "tmpfs": field Source must not be specified`:  {Type: mounttypes.TypeTmpfs, Source: "foo", Target: "/foo"},
+		`type "tmpfs": field VolumeOptions must not be`:     {Type: mounttypes.TypeTmpfs, Target: "/foo", VolumeOptions: &mounttypes.VolumeOptions{}},
+		"invalid tmpfs size: -1, size must not be negative": {Type: mounttypes.TypeTmpfs, Target: "/foo", TmpfsOptions: &mounttypes.TmpfsOptions{SizeBytes: -1}},
+		"invalid tmpfs mode: 10000":                         {Type: mounttypes.TypeTmpfs, Target: "/foo", TmpfsOptions: &mounttypes.TmpfsOptions{Mode: 010000}},
+	}
+	for expected, mnt := range invalid {
+		err := ValidateMountConfig(&mnt)
+		if err == nil || !strings.Contains(err.Error(), expected) {
+			t.Fatalf("Expected an error containing %q for %+v, got %v", expected, mnt, err)
+		}
+	}
+}
+
+func TestParseMountConfig(t *testing.T) {
+	tmpdir, err := ioutil.TempDir("", "test-parse-mount")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpdir)
+
+	mp, err := ParseMountConfig(mounttypes.Mount{Type: mounttypes.TypeBind, Source: tmpdir + "/", Target: "/foo/", ReadOnly: true}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mp.Source != tmpdir || mp.Destination != "/foo" || mp.RW || mp.Type != mounttypes.TypeBind || mp.Propagation != DefaultPropagationMode {
+		t.Fatalf("Unexpected bind mount point %+v", mp)
+	}
+
+	mp, err = ParseMountConfig(mounttypes.Mount{
+		Type:   mounttypes.TypeVolume,
+		Source: "foo",
+		Target: "/foo",
+		VolumeOptions: &mounttypes.VolumeOptions{
+			NoCopy:       true,
+			DriverConfig: &mounttypes.Driver{Name: "bar"},
+		},
+	}, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mp.Name != "foo" || !mp.Named || mp.Driver != "bar" || mp.CopyData || !mp.RW || mp.Type != mounttypes.TypeVolume {
+		t.Fatalf("Unexpected volume mount point %+v", mp)
+	}
+
+	mp, err = ParseMountConfig(mounttypes.Mount{Type: mounttypes.TypeVolume, Target: "/foo"}, "default")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mp.Name != "" || mp.Named || mp.Driver != "default" || !mp.CopyData {
+		t.Fatalf("Unexpected anonymous volume mount point %+v", mp)
+	}
+}
+
+func TestConvertTmpfsOptions(t *testing.T) {
+	cases := []struct {
+		opt      *mounttypes.TmpfsOptions
+		readOnly bool
+		expected string
+	}{
+		{nil, false, ""},
+		{nil, true, "ro"},
+		{&mounttypes.TmpfsOptions{SizeBytes: 1000}, false, "size=1000"},
+		{&mounttypes.TmpfsOptions{SizeBytes: 64 * 1024}, false, "size=64k"},
+		{&mounttypes.TmpfsOptions{SizeBytes: 3 * 1024 * 1024}, true, "ro,size=3m"},
+		{&mounttypes.TmpfsOptions{SizeBytes: 1 << 30, Mode: 0700}, false, "mode=700,size=1g"},
+	}
+	for _, c := range cases {
+		out, err := ConvertTmpfsOptions(c.opt, c.readOnly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != c.expected {
+			t.Fatalf("Expected %q for %+v, got %q", c.expected, c.opt, out)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Sirupsen/logrus"
 	"github.com/docker/docker/pkg/system"
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 // DefaultDriverName is the driver name used for the driver
@@ -67,6 +68,12 @@ type MountPoint struct {
 	// Use a pointer here so we can tell if the user set this value explicitly
 	// This allows us to error out when the user explicitly enabled copy but we can't copy due to the volume being populated
 	CopyData bool `json:"-"`
+
+	// Type is the type of the mount: bind, volume or tmpfs.
+	Type mounttypes.Type `json:",omitempty"`
+	// Spec is the mount configuration the mount point was created from,
+	// only set for mounts given with the structured mount API.
+	Spec mounttypes.Mount
 }
 
 // Setup sets up a mount point by either mounting the volume if it is
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 // read-write modes
@@ -95,6 +97,7 @@ func ParseMountSpec(spec, volumeDriver string) (*MountPoint, error) {
 	if len(source) == 0 {
 		mp.Source = "" // Clear it out as we previously assumed it was not a name
 		mp.Driver = volumeDriver
+		mp.Type = mounttypes.TypeVolume
 		// Named volumes can't have propagation properties specified.
 		// Their defaults will be decided by docker. This is just a
 		// safeguard. Don't want to get into situations where named
@@ -108,6 +111,7 @@ func ParseMountSpec(spec, volumeDriver string) (*MountPoint, error) {
 		}
 	} else {
 		mp.Source = filepath.Clean(source)
+		mp.Type = mounttypes.TypeBind
 	}
 
 	copyData, isSet := getCopyMode(mp.Mode)
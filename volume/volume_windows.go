@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/Sirupsen/logrus"
+	mounttypes "github.com/docker/engine-api/types/mount"
 )
 
 // read-write modes
@@ -109,6 +110,7 @@ func ParseMountSpec(spec string, volumeDriver string) (*MountPoint, error) {
 		Source:      matchgroups["source"],
 		Destination: matchgroups["destination"],
 		RW:          true,
+		Type:        mounttypes.TypeVolume,
 	}
 	if strings.ToLower(matchgroups["mode"]) == "ro" {
 		mp.RW = false
@@ -155,6 +157,7 @@ func ParseMountSpec(spec string, volumeDriver string) (*MountPoint, error) {
 		} else {
 			// OK, so the source must be a host directory. Make sure it's clean.
 			mp.Source = filepath.Clean(mp.Source)
+			mp.Type = mounttypes.TypeBind
 		}
 	}
 